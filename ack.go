@@ -0,0 +1,163 @@
+package makcu
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 🐱 Acknowledgment counters and latencies
+type AckStats struct {
+	Acked    uint64        // commands the MAKCU acknowledged
+	Retries  uint64        // idempotent commands that were sent a second time after their first ack timed out
+	Failures uint64        // commands that never got acknowledged
+	Last     time.Duration // write to ack time of the most recent acknowledged command
+	Max      time.Duration
+	Total    time.Duration // sum over all acknowledged commands, Total / Acked is the mean
+}
+
+// 🐱 Reports whether cmd sets an absolute state, so sending it twice does no harm. Relative ones (km.move, km.wheel)
+// never get retried. The list of such commands is generated from commands.json.
+func isIdempotent(cmd string) bool {
+	fn, _, err := parseCall(cmd)
+	return err == nil && idempotentCommands[fn]
+}
+
+// Turns acknowledgment mode on for every command sent through this handle. Each command is written on its own
+// and waits up to timeout for the MAKCU to echo it back and print its prompt. An idempotent command (button
+// state, lock) that times out is sent once more before giving up with an error matching ErrTimeout.
+// 0 turns it off again. Commands are never held back by the auto flush window while it is on.
+func (m *MakcuHandle) SetAck(timeout time.Duration) error {
+	if m == nil {
+		return fmt.Errorf("SetAck: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.flushAllLocked(); err != nil {
+		return fmt.Errorf("SetAck: %w", err)
+	}
+
+	m.ackTimeout = timeout
+	return nil
+}
+
+// Sends one or more raw commands and waits for each to be acknowledged, whatever mode the handle is in.
+// Returns how long the MAKCU took to acknowledge them all.
+//
+//	latency, err := MakcuConn.WriteAck([]byte("km.left(0)\r"), 50*time.Millisecond)
+func (m *MakcuHandle) WriteAck(cmd []byte, timeout time.Duration) (time.Duration, error) {
+	if m == nil {
+		return 0, fmt.Errorf("WriteAck: %w", ErrNotConnected)
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("WriteAck: timeout must be positive, got %v", timeout)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.flushAllLocked(); err != nil {
+		return 0, fmt.Errorf("WriteAck: %w", err)
+	}
+
+	start := time.Now()
+	if err := m.writeAcked(cmd, timeout); err != nil {
+		return 0, fmt.Errorf("WriteAck: %w", err)
+	}
+
+	return time.Since(start), nil
+}
+
+// 🐱 Returns a snapshot of the acknowledgment counters
+func (m *MakcuHandle) AckStats() AckStats {
+	if m == nil {
+		return AckStats{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ackStats
+}
+
+// 🐱🐱🐱 Cat ack mode! 🐱🐱🐱
+
+// 🐱 Writes every command in buf on its own and waits for its acknowledgment. In checked mode the reply
+// is also searched for a firmware error.
+func (m *MakcuHandle) writeAcked(buf []byte, timeout time.Duration) error {
+	var first error
+	for _, cmd := range bytes.Split(buf, []byte{'\r'}) {
+		cmd = bytes.TrimSpace(cmd)
+		if len(cmd) == 0 {
+			continue
+		}
+
+		reply, err := m.sendAndAwait(cmd, timeout)
+		if err != nil {
+			return err
+		}
+
+		if m.checked {
+			if err := parseReply(string(cmd), reply); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+
+	return first
+}
+
+// 🐱 Writes a single command and waits for the reply that echoes it, retrying once if that is safe
+func (m *MakcuHandle) sendAndAwait(cmd []byte, timeout time.Duration) (string, error) {
+	line := append(append(m.ackBuf[:0], cmd...), '\r')
+	m.ackBuf = line
+
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		if _, err := m.Write(line); err != nil {
+			return "", err
+		}
+
+		reply, err := m.awaitEcho(string(cmd), start.Add(timeout))
+		if err == nil {
+			latency := time.Since(start)
+			m.ackStats.Acked++
+			m.ackStats.Last = latency
+			m.ackStats.Total += latency
+			m.ackStats.Max = max(m.ackStats.Max, latency)
+			return reply, nil
+		}
+
+		if attempt == 0 && errors.Is(err, ErrTimeout) && isIdempotent(string(cmd)) {
+			DebugPrint("No ack for %s after %v, sending it again", cmd, timeout)
+			m.ackStats.Retries++
+			continue
+		}
+
+		m.ackStats.Failures++
+		return "", fmt.Errorf("%s: no acknowledgment: %w", commandName(string(cmd)), err)
+	}
+}
+
+// 🐱 Waits for the reply that echoes cmd, skipping stale replies to earlier commands
+func (m *MakcuHandle) awaitEcho(cmd string, deadline time.Time) (string, error) {
+	for {
+		reply, err := m.nextReply(deadline)
+		if err != nil {
+			return "", err
+		}
+
+		if strings.Contains(reply, cmd) {
+			return reply, nil
+		}
+
+		DebugPrint("Skipping reply %q while waiting for %s", reply, cmd)
+	}
+}
+
+// 🐱🐱🐱 Cat acknowledgment! 🐱🐱🐱
@@ -0,0 +1,103 @@
+package makcu
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 🐱 Long enough for the fake to answer, short enough to keep the timeouts quick
+const ackTimeout = 50 * time.Millisecond
+
+func TestAck(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		drop    int
+		fn      func(m *MakcuHandle) error
+		calls   []string
+		timeout bool
+		stats   AckStats // Last, Max and Total are only checked for being set
+	}{
+		{"acked", 0, func(m *MakcuHandle) error { return m.LeftClick() },
+			[]string{"Left(1)", "Left(0)"}, false, AckStats{Acked: 2}},
+		{"idempotent retried", 1, func(m *MakcuHandle) error { return m.LeftDown() },
+			[]string{"Left(1)", "Left(1)"}, false, AckStats{Acked: 1, Retries: 1}},
+		{"idempotent retried once", 2, func(m *MakcuHandle) error { return m.Lock(LockX) },
+			[]string{"LockX(1)", "LockX(1)"}, true, AckStats{Retries: 1, Failures: 1}},
+		{"move not retried", 1, func(m *MakcuHandle) error { return m.MoveMouse(5, -5) },
+			[]string{"Move(5, -5)"}, true, AckStats{Failures: 1}},
+		{"wheel not retried", 1, func(m *MakcuHandle) error { return m.ScrollMouse(-1) },
+			[]string{"Wheel(-1)"}, true, AckStats{Failures: 1}},
+		{"stops at the first failure", 1, func(m *MakcuHandle) error { return m.Batch().Move(1, 1).Move(2, 2).Flush() },
+			[]string{"Move(1, 1)"}, true, AckStats{Failures: 1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, dev := openFake(t, "v3.0.0")
+			if err := m.SetAck(ackTimeout); err != nil {
+				t.Fatal(err)
+			}
+			dev.DropReplies(tc.drop)
+
+			start := time.Now()
+			err := tc.fn(m)
+			if tc.timeout != errors.Is(err, ErrTimeout) || !tc.timeout && err != nil {
+				t.Fatalf("got %v, want a timeout: %v", err, tc.timeout)
+			}
+
+			// a failure gives up after every attempt had its full timeout
+			attempts := tc.stats.Retries + tc.stats.Failures
+			if elapsed := time.Since(start); elapsed < time.Duration(attempts)*ackTimeout {
+				t.Errorf("gave up after %v, want at least %d x %v", elapsed, attempts, ackTimeout)
+			}
+
+			if got := callNames(dev); !reflect.DeepEqual(got, tc.calls) {
+				t.Errorf("calls = %v, want %v", got, tc.calls)
+			}
+
+			stats := m.AckStats()
+			if stats.Acked != tc.stats.Acked || stats.Retries != tc.stats.Retries || stats.Failures != tc.stats.Failures {
+				t.Errorf("stats = %+v, want %+v", stats, tc.stats)
+			}
+
+			if stats.Acked > 0 && (stats.Last <= 0 || stats.Max < stats.Last || stats.Total < stats.Max || stats.Max > ackTimeout) {
+				t.Errorf("latencies don't add up: %+v", stats)
+			}
+			if stats.Acked == 0 && (stats.Last != 0 || stats.Total != 0) {
+				t.Errorf("latencies without an ack: %+v", stats)
+			}
+		})
+	}
+}
+
+func TestWriteAck(t *testing.T) {
+	m, dev := openFake(t, "")
+
+	latency, err := m.WriteAck([]byte("km.right(1)\rkm.right(0)\r"), ackTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latency <= 0 || latency > 2*ackTimeout {
+		t.Errorf("latency = %v", latency)
+	}
+
+	// a relative command is never sent twice, even through WriteAck
+	dev.DropReplies(1)
+	_, err = m.WriteAck([]byte("km.wheel(3)\r"), ackTimeout)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("lost echo: got %v, want ErrTimeout", err)
+	}
+
+	want := []string{"Right(1)", "Right(0)", "Wheel(3)"}
+	if got := callNames(dev); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+
+	if stats := m.AckStats(); stats.Acked != 2 || stats.Failures != 1 || stats.Retries != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	if _, err := m.WriteAck([]byte("km.left(1)\r"), 0); err == nil {
+		t.Error("WriteAck with no timeout: no error")
+	}
+}
@@ -0,0 +1,258 @@
+package makcu
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// 🐱 Largest single write the library makes when it joins commands together. Commands are never cut in half, so one
+// command longer than this still goes out on its own.
+var MaxFrameSize = 256
+
+// 🐱 Collects commands and sends them together in as few writes as possible. Build one with MakcuConn.Batch(),
+// chain the commands and finish with Flush(). A Batch is not safe for use from several goroutines.
+type Batch struct {
+	m   *MakcuHandle
+	buf []byte
+	err error // first bad argument, returned by Flush
+}
+
+// 🐱 Starts an empty batch on this handle
+func (m *MakcuHandle) Batch() *Batch {
+	return &Batch{m: m}
+}
+
+// 🐱 Appends a raw command. The trailing \r is added if missing.
+func (b *Batch) Raw(cmd string) *Batch {
+	b.buf = append(b.buf, cmd...)
+	if len(cmd) == 0 || cmd[len(cmd)-1] != '\r' {
+		b.buf = append(b.buf, '\r')
+	}
+
+	return b
+}
+
+// 🐱 Appends a relative move. One that is out of range is split when SetSplitMoves is on, otherwise Flush
+// returns its *RangeError and sends nothing.
+func (b *Batch) Move(x, y int) *Batch {
+	if err := checkMove("Batch.Move", x, y); err != nil && !b.m.splitting() {
+		b.fail(err)
+		return b
+	}
+
+	b.buf = appendMoves(b.buf, x, y)
+	return b
+}
+
+// 🐱 Appends a wheel move. Flush returns a *RangeError for an amount outside [-127, 127].
+func (b *Batch) Scroll(amount int) *Batch {
+	if err := checkRange("Batch.Scroll", "amount", amount, -127, 127); err != nil {
+		b.fail(err)
+		return b
+	}
+
+	b.buf = appendCall(b.buf, "wheel", amount)
+	return b
+}
+
+func (b *Batch) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *Batch) LeftDown() *Batch   { return b.Raw("km.left(1)\r") }
+func (b *Batch) LeftUp() *Batch     { return b.Raw("km.left(0)\r") }
+func (b *Batch) RightDown() *Batch  { return b.Raw("km.right(1)\r") }
+func (b *Batch) RightUp() *Batch    { return b.Raw("km.right(0)\r") }
+func (b *Batch) MiddleDown() *Batch { return b.Raw("km.middle(1)\r") }
+func (b *Batch) MiddleUp() *Batch   { return b.Raw("km.middle(0)\r") }
+
+// 🐱 Number of bytes waiting to be flushed
+func (b *Batch) Len() int {
+	return len(b.buf)
+}
+
+// 🐱 Sends everything collected so far and empties the batch so it can be reused
+func (b *Batch) Flush() error {
+	if b.m == nil {
+		return fmt.Errorf("Batch.Flush: %w", ErrNotConnected)
+	}
+
+	if err := b.err; err != nil {
+		b.buf, b.err = b.buf[:0], nil
+		return fmt.Errorf("Batch.Flush: %w", err)
+	}
+
+	if len(b.buf) == 0 {
+		return nil
+	}
+
+	b.m.mu.Lock()
+	defer b.m.mu.Unlock()
+
+	// anything the auto flush window or the coalescer is still holding was issued before this batch
+	err := b.m.flushAllLocked()
+	if err == nil {
+		err = b.m.writeFrames(b.buf)
+	}
+
+	b.buf = b.buf[:0]
+	if err != nil {
+		return fmt.Errorf("Batch.Flush: %w", err)
+	}
+
+	return nil
+}
+
+// 🐱🐱🐱 Cat batch! 🐱🐱🐱
+
+// 🐱 Writes buf in chunks of at most MaxFrameSize bytes, only ever splitting after a \r
+func (m *MakcuHandle) writeFrames(buf []byte) error {
+	// acknowledged commands go out one at a time
+	if m.ackTimeout > 0 {
+		return m.writeAcked(buf, m.ackTimeout)
+	}
+
+	for len(buf) > 0 {
+		n := len(buf)
+		if n > MaxFrameSize {
+			n = bytes.LastIndexByte(buf[:MaxFrameSize], '\r') + 1
+			if n == 0 {
+				// a single command bigger than a frame, send the whole command
+				n = bytes.IndexByte(buf, '\r') + 1
+				if n == 0 {
+					n = len(buf)
+				}
+			}
+		}
+
+		if _, err := m.Write(buf[:n]); err != nil {
+			return err
+		}
+
+		if m.checked {
+			if err := m.checkReplies(buf[:n]); err != nil {
+				return err
+			}
+		}
+
+		buf = buf[n:]
+	}
+
+	return nil
+}
+
+// 🐱 Sends a command now, or queues it when an auto flush window is set
+func (m *MakcuHandle) exec(cmd []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.execLocked(cmd)
+}
+
+// 🐱 Same as exec with m.mu already held. A coalesced move issued earlier always goes out first.
+func (m *MakcuHandle) execLocked(cmd []byte) error {
+	if err := m.flushMoveLocked(); err != nil {
+		return err
+	}
+
+	return m.sendLocked(cmd)
+}
+
+func (m *MakcuHandle) sendLocked(cmd []byte) error {
+	// an earlier timed flush failed, report it to the next caller instead of losing it
+	if err := m.flushErr; err != nil {
+		m.flushErr = nil
+		return err
+	}
+
+	if m.flushWindow <= 0 || m.checked || m.ackTimeout > 0 {
+		return m.writeFrames(cmd)
+	}
+
+	if len(m.pending)+len(cmd) > MaxFrameSize {
+		if err := m.flushLocked(); err != nil {
+			return err
+		}
+	}
+
+	if len(m.pending) == 0 {
+		m.flushTimer = time.AfterFunc(m.flushWindow, m.timedFlush)
+	}
+
+	m.pending = append(m.pending, cmd...)
+	return nil
+}
+
+// Sets how long commands may wait to be joined with the ones after them into a single write.
+// 0 (the default) turns auto flushing off and sends every command straight away.
+// Whatever is waiting is flushed when the window is changed.
+func (m *MakcuHandle) SetAutoFlush(window time.Duration) error {
+	if m == nil {
+		return fmt.Errorf("SetAutoFlush: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := m.flushAllLocked()
+	m.flushWindow = window
+	if err != nil {
+		return fmt.Errorf("SetAutoFlush: %w", err)
+	}
+
+	return nil
+}
+
+// 🐱 Sends any commands the auto flush window (or a pending coalesced move) is holding right now
+func (m *MakcuHandle) Flush() error {
+	if m == nil {
+		return fmt.Errorf("Flush: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.flushAllLocked(); err != nil {
+		return fmt.Errorf("Flush: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MakcuHandle) flushAllLocked() error {
+	if err := m.flushMoveLocked(); err != nil {
+		return err
+	}
+
+	return m.flushLocked()
+}
+
+func (m *MakcuHandle) flushLocked() error {
+	if m.flushTimer != nil {
+		m.flushTimer.Stop()
+		m.flushTimer = nil
+	}
+
+	if len(m.pending) == 0 {
+		return nil
+	}
+
+	err := m.writeFrames(m.pending)
+	m.pending = m.pending[:0]
+	return err
+}
+
+func (m *MakcuHandle) timedFlush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.flushLocked(); err != nil {
+		ErrorPrint("Auto flush failed: %v", err)
+		m.flushErr = err
+	}
+}
+
+// 🐱🐱🐱 Cat auto flush! 🐱🐱🐱
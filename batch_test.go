@@ -0,0 +1,88 @@
+package makcu
+
+import (
+	"testing"
+	"time"
+)
+
+// 🐱 Port that throws everything away and counts the writes, standing in for the syscalls a COM port would make
+type countingPort struct {
+	writes  int
+	bytes   int
+	largest int
+}
+
+func (p *countingPort) Write(b []byte) (int, error) {
+	p.writes++
+	p.bytes += len(b)
+	p.largest = max(p.largest, len(b))
+	return len(b), nil
+}
+
+func (p *countingPort) Read(b []byte) (int, error) { return 0, nil }
+func (p *countingPort) Close() error               { return nil }
+
+// 🐱 Moves sent per benchmark op, roughly one frame of an aim or drawing loop
+const movesPerOp = 10
+
+func reportWrites(b *testing.B, p *countingPort) {
+	b.ReportMetric(float64(p.writes)/float64(b.N), "writes/op")
+	b.ReportMetric(float64(p.bytes)/float64(b.N), "bytes/op")
+}
+
+func BenchmarkWritesUnbatched(b *testing.B) {
+	p := &countingPort{}
+	m := Open(p)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < movesPerOp; j++ {
+			if err := m.MoveMouse(1, -1); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	reportWrites(b, p)
+}
+
+func BenchmarkWritesBatch(b *testing.B) {
+	p := &countingPort{}
+	m := Open(p)
+	batch := m.Batch()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < movesPerOp; j++ {
+			batch.Move(1, -1)
+		}
+		if err := batch.Flush(); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	reportWrites(b, p)
+}
+
+func BenchmarkWritesAutoFlush(b *testing.B) {
+	p := &countingPort{}
+	m := Open(p)
+	// long enough that the timer never fires, Flush ends each op like the window running out would
+	if err := m.SetAutoFlush(time.Hour); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < movesPerOp; j++ {
+			if err := m.MoveMouse(1, -1); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := m.Flush(); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	reportWrites(b, p)
+}
@@ -0,0 +1,326 @@
+package makcu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 🐱 A mouse button. The values match the MOUSE_BUTTON_* constants.
+type Button uint8
+
+const (
+	ButtonLeft   Button = MOUSE_BUTTON_LEFT
+	ButtonRight  Button = MOUSE_BUTTON_RIGHT
+	ButtonMiddle Button = MOUSE_BUTTON_MIDDLE
+	ButtonSide1  Button = MOUSE_BUTTON_SIDE1 // mouse 4, usually back. Needs CapSideButtons
+	ButtonSide2  Button = MOUSE_BUTTON_SIDE2 // mouse 5, usually forward. Needs CapSideButtons
+)
+
+// 🐱 Every button, in order
+var AllButtons = []Button{ButtonLeft, ButtonRight, ButtonMiddle, ButtonSide1, ButtonSide2}
+
+// 🐱 Per button details, indexed by Button
+var buttonInfo = [...]struct {
+	name string
+	fn   string // km.<fn>(state)
+	set  func(*MakcuHandle, int) error
+	cap  Capability
+}{
+	ButtonLeft:   {"left", "left", (*MakcuHandle).KmLeft, 0},
+	ButtonRight:  {"right", "right", (*MakcuHandle).KmRight, 0},
+	ButtonMiddle: {"middle", "middle", (*MakcuHandle).KmMiddle, 0},
+	ButtonSide1:  {"side1", "side1", (*MakcuHandle).KmSide1, CapSideButtons},
+	ButtonSide2:  {"side2", "side2", (*MakcuHandle).KmSide2, CapSideButtons},
+}
+
+func (b Button) valid() bool {
+	return b >= ButtonLeft && b <= ButtonSide2
+}
+
+func (b Button) String() string {
+	if !b.valid() {
+		return fmt.Sprintf("Button(%d)", uint8(b))
+	}
+
+	return buttonInfo[b].name
+}
+
+// 🐱 Parses a button name as printed by String ("left", "side1", ...). Case doesn't matter, and "mouse1".."mouse5"
+// are accepted too.
+func ParseButton(s string) (Button, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for _, b := range AllButtons {
+		if name == buttonInfo[b].name || name == fmt.Sprintf("mouse%d", b) {
+			return b, nil
+		}
+	}
+
+	return 0, fmt.Errorf("ParseButton: %w: %q", ErrInvalidButton, s)
+}
+
+// 🐱 Returns the button's bit in a ButtonMask
+func (b Button) Mask() ButtonMask {
+	if !b.valid() {
+		return 0
+	}
+
+	return 1 << (b - 1)
+}
+
+// 🐱 A set of buttons, one bit each (left is bit 0, side2 is bit 4). The firmware's button stream uses the same layout.
+type ButtonMask uint8
+
+// 🐱 Reports whether b is in the set
+func (mask ButtonMask) Has(b Button) bool {
+	return b.valid() && mask&b.Mask() != 0
+}
+
+func (mask ButtonMask) String() string {
+	var names []string
+	for _, b := range AllButtons {
+		if mask.Has(b) {
+			names = append(names, b.String())
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "|")
+}
+
+// 🐱🐱🐱 Cat buttons! 🐱🐱🐱
+
+// 🐱 Holds a mouse button down
+func (m *MakcuHandle) Press(b Button) error {
+	return m.setButton("Press", b, 1)
+}
+
+// 🐱 Lets go of a mouse button
+func (m *MakcuHandle) Release(b Button) error {
+	return m.setButton("Release", b, 0)
+}
+
+func (m *MakcuHandle) setButton(op string, b Button, state int) error {
+	if m == nil {
+		return fmt.Errorf("%s: %w", op, ErrNotConnected)
+	}
+
+	if !b.valid() {
+		return fmt.Errorf("%s: %w: %d", op, ErrInvalidButton, b)
+	}
+
+	err := buttonInfo[b].set(m, state)
+	if err != nil {
+		DebugPrint("Failed to %s %s button: %v", strings.ToLower(op), b, err)
+		return err
+	}
+
+	return nil
+}
+
+// Clicks a mouse button, holding it down for hold. With hold 0 the press and release go out in a single write.
+func (m *MakcuHandle) Tap(b Button, hold time.Duration) error {
+	if m == nil {
+		return fmt.Errorf("Tap: %w", ErrNotConnected)
+	}
+
+	if !b.valid() {
+		return fmt.Errorf("Tap: %w: %d", ErrInvalidButton, b)
+	}
+
+	if hold <= 0 {
+		if err := m.require("Tap", buttonInfo[b].cap); err != nil {
+			return err
+		}
+
+		err := m.callClick(buttonInfo[b].fn)
+		if err != nil {
+			DebugPrint("Failed to click %s button: %v", b, err)
+			return err
+		}
+
+		return nil
+	}
+
+	if err := m.Press(b); err != nil {
+		return err
+	}
+
+	time.Sleep(hold)
+
+	return m.Release(b)
+}
+
+// 🐱 How far apart clicks may start and still count as one double or triple click. 500ms is the Windows default
+// and GNOME's is 400ms, so MultiClick stays under the smaller one.
+const DoubleClickWindow = 400 * time.Millisecond
+
+// 🐱 Most clicks one MultiClick sends. Hosts stop counting after a triple click anyway, and this many clicks
+// batched together still fit a single write of the default MaxFrameSize.
+const MaxMultiClick = 10
+
+// Clicks b count times, holding each click for hold and leaving interval between a release and the next press.
+// Presses are scheduled from the first one, so the timing doesn't drift with the write time. With hold and
+// interval both 0 the clicks are batched, split at MaxFrameSize like any other batch. count goes up to
+// MaxMultiClick, and for count > 1 hold + interval has to stay under DoubleClickWindow or the host would see
+// separate clicks instead of a double click; use Tap in a loop for that.
+//
+//	err := MakcuConn.MultiClick(makcu.ButtonLeft, 2, 20*time.Millisecond, 60*time.Millisecond)
+func (m *MakcuHandle) MultiClick(b Button, count int, hold, interval time.Duration) error {
+	if m == nil {
+		return fmt.Errorf("MultiClick: %w", ErrNotConnected)
+	}
+
+	if !b.valid() {
+		return fmt.Errorf("MultiClick: %w: %d", ErrInvalidButton, b)
+	}
+
+	if count < 1 || count > MaxMultiClick {
+		return fmt.Errorf("MultiClick: %w: count must be 1 to %d, got %d", ErrOutOfRange, MaxMultiClick, count)
+	}
+
+	if hold < 0 || interval < 0 {
+		return fmt.Errorf("MultiClick: %w: hold and interval must not be negative, got %v and %v", ErrOutOfRange, hold, interval)
+	}
+
+	period := hold + interval
+	if count > 1 && period >= DoubleClickWindow {
+		return fmt.Errorf("MultiClick: %w: clicks %v apart don't fit the %v double-click window", ErrOutOfRange, period, DoubleClickWindow)
+	}
+
+	if err := m.require("MultiClick", buttonInfo[b].cap); err != nil {
+		return err
+	}
+
+	fn := buttonInfo[b].fn
+	if period == 0 {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.cmdBuf = m.cmdBuf[:0]
+		for i := 0; i < count; i++ {
+			m.cmdBuf = appendCall(m.cmdBuf, fn, 1)
+			m.cmdBuf = appendCall(m.cmdBuf, fn, 0)
+		}
+
+		return m.execLocked(m.cmdBuf)
+	}
+
+	start := time.Now()
+	for i := 0; i < count; i++ {
+		time.Sleep(time.Until(start.Add(time.Duration(i) * period)))
+
+		if hold == 0 {
+			if err := m.callClick(fn); err != nil {
+				DebugPrint("Failed to click %s button: %v", b, err)
+				return err
+			}
+			continue
+		}
+
+		if err := m.Press(b); err != nil {
+			return err
+		}
+
+		time.Sleep(time.Until(start.Add(time.Duration(i)*period + hold)))
+
+		if err := m.Release(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sets every button at once: the ones in mask are held down, all others are released, in a single write.
+// Side buttons are left alone on firmware without them, unless mask asks for one.
+func (m *MakcuHandle) SetButtons(mask ButtonMask) error {
+	if m == nil {
+		return fmt.Errorf("SetButtons: %w", ErrNotConnected)
+	}
+
+	if extra := mask &^ (ButtonSide2.Mask()<<1 - 1); extra != 0 {
+		return fmt.Errorf("SetButtons: %w: mask 0x%02X", ErrInvalidButton, uint8(mask))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cmdBuf = m.cmdBuf[:0]
+	for _, b := range AllButtons {
+		if err := m.require("SetButtons", buttonInfo[b].cap); err != nil {
+			if mask.Has(b) {
+				return err
+			}
+			continue
+		}
+
+		state := 0
+		if mask.Has(b) {
+			state = 1
+		}
+		m.cmdBuf = appendCall(m.cmdBuf, buttonInfo[b].fn, state)
+	}
+
+	return m.execLocked(m.cmdBuf)
+}
+
+// 🐱🐱🐱 Cat button state! 🐱🐱🐱
+
+// Asks the MAKCU whether b is currently held down, either by the user's physical mouse or by this library.
+func (m *MakcuHandle) IsPressed(b Button) (bool, error) {
+	if m == nil {
+		return false, fmt.Errorf("IsPressed: %w", ErrNotConnected)
+	}
+
+	if !b.valid() {
+		return false, fmt.Errorf("IsPressed: %w: %d", ErrInvalidButton, b)
+	}
+
+	if err := m.require("IsPressed", buttonInfo[b].cap); err != nil {
+		return false, err
+	}
+
+	v, err := m.query("IsPressed", buttonInfo[b].fn)
+	if err != nil {
+		return false, err
+	}
+
+	return v != 0, nil
+}
+
+// Asks the MAKCU which buttons are currently held down. All buttons are queried in a single write, so the answer
+// is a consistent snapshot. Side buttons are never reported on firmware without them.
+func (m *MakcuHandle) Buttons() (ButtonMask, error) {
+	if m == nil {
+		return 0, fmt.Errorf("Buttons: %w", ErrNotConnected)
+	}
+
+	var asked []Button
+	var fns []string
+	for _, b := range AllButtons {
+		if m.Supports(buttonInfo[b].cap) {
+			asked = append(asked, b)
+			fns = append(fns, buttonInfo[b].fn)
+		}
+	}
+
+	vals, err := m.queryAll("Buttons", fns...)
+	if err != nil {
+		return 0, err
+	}
+
+	var mask ButtonMask
+	for i, b := range asked {
+		if vals[i] != 0 {
+			mask |= b.Mask()
+		}
+	}
+
+	return mask, nil
+}
+
+// 🐱🐱🐱 Cat button query! 🐱🐱🐱
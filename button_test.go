@@ -0,0 +1,207 @@
+package makcu
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSideButtons(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		fn   func(m *MakcuHandle) error
+		want []string
+	}{
+		{"Side1Down", (*MakcuHandle).Side1Down, []string{"Side1(1)"}},
+		{"Side1Up", (*MakcuHandle).Side1Up, []string{"Side1(0)"}},
+		{"Side1Click", (*MakcuHandle).Side1Click, []string{"Side1(1)", "Side1(0)"}},
+		{"Side2Down", (*MakcuHandle).Side2Down, []string{"Side2(1)"}},
+		{"Side2Up", (*MakcuHandle).Side2Up, []string{"Side2(0)"}},
+		{"Side2Click", (*MakcuHandle).Side2Click, []string{"Side2(1)", "Side2(0)"}},
+		{"Click side1", func(m *MakcuHandle) error { return m.Click(MOUSE_BUTTON_SIDE1, 0) }, []string{"Side1(1)", "Side1(0)"}},
+		{"Click side2", func(m *MakcuHandle) error { return m.Click(MOUSE_BUTTON_SIDE2, 0) }, []string{"Side2(1)", "Side2(0)"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, dev := openFake(t, "v3.0.0")
+			if err := tc.fn(m); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := callNames(dev); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("calls = %v, want %v", got, tc.want)
+			}
+
+			old, oldDev := openFake(t, "v2.9.0")
+			err := tc.fn(old)
+			var ue *UnsupportedError
+			if !errors.Is(err, ErrUnsupported) || !errors.As(err, &ue) || ue.Cap != CapSideButtons {
+				t.Errorf("on v2.9.0: got %v, want ErrUnsupported for side buttons", err)
+			}
+
+			if got := oldDev.Calls(); len(got) != 0 {
+				t.Errorf("on v2.9.0: sent %v anyway", got)
+			}
+		})
+	}
+}
+
+func TestClickRejectsInvalidButtons(t *testing.T) {
+	m, dev := openFake(t, "")
+	for _, i := range []int{0, 6, -1, 256, 257, -255, 1 << 40} {
+		if err := m.Click(i, 0); !errors.Is(err, ErrInvalidButton) {
+			t.Errorf("Click(%d): got %v, want ErrInvalidButton", i, err)
+		}
+	}
+
+	if got := dev.Calls(); len(got) != 0 {
+		t.Errorf("sent %v for invalid buttons", got)
+	}
+
+	for i, want := range []string{"Left", "Right", "Middle", "Side1", "Side2"} {
+		dev.Reset()
+		if err := m.Click(MOUSE_BUTTON_LEFT+i, 0); err != nil {
+			t.Fatalf("Click(%d): %v", MOUSE_BUTTON_LEFT+i, err)
+		}
+		if got := callNames(dev); !reflect.DeepEqual(got, []string{want + "(1)", want + "(0)"}) {
+			t.Errorf("Click(%d) sent %v", MOUSE_BUTTON_LEFT+i, got)
+		}
+	}
+}
+
+func TestMultiClick(t *testing.T) {
+	m, dev := openFake(t, "v3.0.0")
+	if err := m.MultiClick(ButtonLeft, 3, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MultiClick(ButtonSide1, 2, time.Millisecond, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Left(1)", "Left(0)", "Left(1)", "Left(0)", "Left(1)", "Left(0)", "Side1(1)", "Side1(0)", "Side1(1)", "Side1(0)"}
+	if got := callNames(dev); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestMultiClickBadArgs(t *testing.T) {
+	m, dev := openFake(t, "v3.0.0")
+	for _, tc := range []struct {
+		count          int
+		hold, interval time.Duration
+	}{
+		{0, 0, 0},
+		{-1, 0, 0},
+		{MaxMultiClick + 1, 0, 0},
+		{1 << 30, 0, 0},
+		{1, -time.Millisecond, 0},
+		{2, 0, DoubleClickWindow},
+		{2, 300 * time.Millisecond, 100 * time.Millisecond},
+	} {
+		if err := m.MultiClick(ButtonLeft, tc.count, tc.hold, tc.interval); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("MultiClick(%d, %v, %v): got %v, want ErrOutOfRange", tc.count, tc.hold, tc.interval, err)
+		}
+	}
+
+	if got := dev.Calls(); len(got) != 0 {
+		t.Errorf("sent %v anyway", got)
+	}
+}
+
+// 🐱 The most clicks MultiClick allows fit one write at the default frame size, and a smaller MaxFrameSize
+// splits them like any batch
+func TestMultiClickWrites(t *testing.T) {
+	p := &countingPort{}
+	m := Open(p)
+	if err := m.MultiClick(ButtonSide2, MaxMultiClick, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if p.writes != 1 || p.largest > MaxFrameSize {
+		t.Errorf("%d writes of up to %d bytes, want 1 of at most %d", p.writes, p.largest, MaxFrameSize)
+	}
+
+	defer func(size int) { MaxFrameSize = size }(MaxFrameSize)
+	MaxFrameSize = 64
+
+	p = &countingPort{}
+	m = Open(p)
+	if err := m.MultiClick(ButtonLeft, MaxMultiClick, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if p.writes < 2 || p.largest > MaxFrameSize || p.bytes != MaxMultiClick*len("km.left(1)\rkm.left(0)\r") {
+		t.Errorf("%d writes of up to %d bytes, %d in all", p.writes, p.largest, p.bytes)
+	}
+}
+
+func TestButtonState(t *testing.T) {
+	m, dev := openFake(t, "v3.0.0")
+	for _, mask := range []ButtonMask{0, ButtonLeft.Mask(), ButtonRight.Mask() | ButtonSide2.Mask(), 0x1F} {
+		dev.PhysicalButtons(byte(mask))
+
+		got, err := m.Buttons()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != mask {
+			t.Errorf("Buttons() = %05b, want %05b", got, mask)
+		}
+
+		for _, b := range AllButtons {
+			pressed, err := m.IsPressed(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pressed != mask.Has(b) {
+				t.Errorf("with %05b held, IsPressed(%s) = %v", mask, b, pressed)
+			}
+		}
+	}
+
+	// buttons this library holds count too
+	dev.PhysicalButtons(0)
+	if err := m.Press(ButtonMiddle); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := m.Buttons(); err != nil || got != ButtonMiddle.Mask() {
+		t.Errorf("after Press(middle): Buttons() = %05b, %v", got, err)
+	}
+
+	if _, err := m.IsPressed(Button(9)); !errors.Is(err, ErrInvalidButton) {
+		t.Errorf("IsPressed(9): got %v, want ErrInvalidButton", err)
+	}
+}
+
+// 🐱 Firmware without side buttons is never asked about them
+func TestButtonStateNoSideButtons(t *testing.T) {
+	m, dev := openFake(t, "v2.9.0")
+	dev.PhysicalButtons(0x1F)
+
+	got, err := m.Buttons()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ButtonLeft.Mask() | ButtonRight.Mask() | ButtonMiddle.Mask(); got != want {
+		t.Errorf("Buttons() = %05b, want %05b", got, want)
+	}
+
+	want := []string{"LeftState()", "RightState()", "MiddleState()"}
+	if calls := callNames(dev); !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	for _, b := range []Button{ButtonSide1, ButtonSide2} {
+		_, err := m.IsPressed(b)
+		var ue *UnsupportedError
+		if !errors.Is(err, ErrUnsupported) || !errors.As(err, &ue) || ue.Cap != CapSideButtons {
+			t.Errorf("IsPressed(%s) on v2.9.0: got %v, want ErrUnsupported for side buttons", b, err)
+		}
+	}
+
+	if pressed, err := m.IsPressed(ButtonLeft); err != nil || !pressed {
+		t.Errorf("IsPressed(left) = %v, %v", pressed, err)
+	}
+
+	if calls := dev.Calls(); len(calls) != 4 {
+		t.Errorf("calls = %v, want no side button queries", callNames(dev))
+	}
+}
@@ -0,0 +1,217 @@
+// Code generated by cmdgen from commands.json. DO NOT EDIT.
+
+package main
+
+import makcu "github.com/nullpkt/Makcu-Go"
+
+// generated has one subcommand per command in commands.json
+var generated = []subcommand{
+	{
+		name: "left",
+		args: []string{"state"},
+		doc:  "Sets the left button state (1 = down, 0 = up).",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLeft(a[0])
+		},
+	},
+	{
+		name: "right",
+		args: []string{"state"},
+		doc:  "Sets the right button state (1 = down, 0 = up).",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmRight(a[0])
+		},
+	},
+	{
+		name: "middle",
+		args: []string{"state"},
+		doc:  "Sets the middle button state (1 = down, 0 = up).",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmMiddle(a[0])
+		},
+	},
+	{
+		name: "side1",
+		args: []string{"state"},
+		doc:  "Sets the first side button (mouse 4, usually back) state (1 = down, 0 = up).",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmSide1(a[0])
+		},
+	},
+	{
+		name: "side2",
+		args: []string{"state"},
+		doc:  "Sets the second side button (mouse 5, usually forward) state (1 = down, 0 = up).",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmSide2(a[0])
+		},
+	},
+	{
+		name: "left-state",
+		args: []string{},
+		doc:  "Reports whether the left button is held down (1) or not (0), by the user or the host.",
+		query: func(m *makcu.MakcuHandle) (int, error) {
+			return m.KmLeftState()
+		},
+	},
+	{
+		name: "right-state",
+		args: []string{},
+		doc:  "Reports whether the right button is held down (1) or not (0), by the user or the host.",
+		query: func(m *makcu.MakcuHandle) (int, error) {
+			return m.KmRightState()
+		},
+	},
+	{
+		name: "middle-state",
+		args: []string{},
+		doc:  "Reports whether the middle button is held down (1) or not (0), by the user or the host.",
+		query: func(m *makcu.MakcuHandle) (int, error) {
+			return m.KmMiddleState()
+		},
+	},
+	{
+		name: "side1-state",
+		args: []string{},
+		doc:  "Reports whether the first side button is held down (1) or not (0), by the user or the host.",
+		query: func(m *makcu.MakcuHandle) (int, error) {
+			return m.KmSide1State()
+		},
+	},
+	{
+		name: "side2-state",
+		args: []string{},
+		doc:  "Reports whether the second side button is held down (1) or not (0), by the user or the host.",
+		query: func(m *makcu.MakcuHandle) (int, error) {
+			return m.KmSide2State()
+		},
+	},
+	{
+		name: "button-stream",
+		args: []string{"enable"},
+		doc:  "Turns streaming of the physical button state on (1) or off (0). While on, the MAKCU sends km. followed by the button mask byte whenever it changes.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmButtonStream(a[0])
+		},
+	},
+	{
+		name: "motion-stream",
+		args: []string{"enable"},
+		doc:  "Turns streaming of the physical mouse movement on (1) or off (0). While on, every report from the mouse is sent as a binary motion frame.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmMotionStream(a[0])
+		},
+	},
+	{
+		name: "lock-x",
+		args: []string{"locked"},
+		doc:  "Blocks (1) or unblocks (0) the physical mouse's horizontal movement from reaching the host. Commands from this library still go through.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLockX(a[0])
+		},
+	},
+	{
+		name: "lock-y",
+		args: []string{"locked"},
+		doc:  "Blocks (1) or unblocks (0) the physical mouse's vertical movement from reaching the host. Commands from this library still go through.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLockY(a[0])
+		},
+	},
+	{
+		name: "lock-left",
+		args: []string{"locked"},
+		doc:  "Blocks (1) or unblocks (0) the physical mouse's left button from reaching the host. Commands from this library still go through.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLockLeft(a[0])
+		},
+	},
+	{
+		name: "lock-right",
+		args: []string{"locked"},
+		doc:  "Blocks (1) or unblocks (0) the physical mouse's right button from reaching the host. Commands from this library still go through.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLockRight(a[0])
+		},
+	},
+	{
+		name: "lock-middle",
+		args: []string{"locked"},
+		doc:  "Blocks (1) or unblocks (0) the physical mouse's middle button from reaching the host. Commands from this library still go through.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLockMiddle(a[0])
+		},
+	},
+	{
+		name: "lock-side1",
+		args: []string{"locked"},
+		doc:  "Blocks (1) or unblocks (0) the physical mouse's first side button from reaching the host. Commands from this library still go through.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLockSide1(a[0])
+		},
+	},
+	{
+		name: "lock-side2",
+		args: []string{"locked"},
+		doc:  "Blocks (1) or unblocks (0) the physical mouse's second side button from reaching the host. Commands from this library still go through.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLockSide2(a[0])
+		},
+	},
+	{
+		name: "lock-wheel",
+		args: []string{"locked"},
+		doc:  "Blocks (1) or unblocks (0) the physical mouse's wheel from reaching the host. Commands from this library still go through.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmLockWheel(a[0])
+		},
+	},
+	{
+		name: "key-down",
+		args: []string{"key"},
+		doc:  "Holds down the keyboard key with HID usage code key.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmKeyDown(a[0])
+		},
+	},
+	{
+		name: "key-up",
+		args: []string{"key"},
+		doc:  "Releases the keyboard key with HID usage code key.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmKeyUp(a[0])
+		},
+	},
+	{
+		name: "wheel",
+		args: []string{"amount"},
+		doc:  "Scrolls the wheel by amount notches (positive is up).",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmWheel(a[0])
+		},
+	},
+	{
+		name: "move",
+		args: []string{"x", "y"},
+		doc:  "Moves the cursor by (x, y).",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmMove(a[0], a[1])
+		},
+	},
+	{
+		name: "move-segments",
+		args: []string{"x", "y", "segments"},
+		doc:  "Moves the cursor by (x, y) in a straight line split into segments steps.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmMoveSegments(a[0], a[1], a[2])
+		},
+	},
+	{
+		name: "move-bezier",
+		args: []string{"x", "y", "segments", "cx", "cy"},
+		doc:  "Moves the cursor by (x, y) along a quadratic Bezier curve through the control point (cx, cy), in segments steps.",
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.KmMoveBezier(a[0], a[1], a[2], a[3], a[4])
+		},
+	},
+}
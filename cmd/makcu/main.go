@@ -0,0 +1,181 @@
+// Command makcu sends single commands to a MAKCU from the command line.
+//
+//	makcu move 100 -50
+//	makcu -port COM5 -baud 4000000 left 1
+//	makcu -fake move-bezier 200 0 20 100 100
+//	makcu -trace out.jsonl move 10 10
+//	makcu -hex trace out.jsonl
+//
+// There is one subcommand per command in commands.json (generated into commands_gen.go), plus "version" and
+// "trace", which prints a trace saved with -trace (or Trace.ExportJSONL) without needing a MAKCU.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	makcu "github.com/nullpkt/Makcu-Go"
+	"github.com/nullpkt/Makcu-Go/makcufake"
+)
+
+// 🐱 One CLI subcommand. Every argument is an integer. Queries have query set instead of run.
+type subcommand struct {
+	name  string
+	args  []string
+	doc   string
+	run   func(m *makcu.MakcuHandle, args []int) error
+	query func(m *makcu.MakcuHandle) (int, error)
+}
+
+func main() {
+	port := flag.String("port", "", "COM port of the MAKCU (found automatically if empty)")
+	baud := flag.Uint("baud", 0, "baud rate (0 auto-detects)")
+	fake := flag.Bool("fake", false, "talk to an in-memory fake MAKCU and print what it received")
+	traceFile := flag.String("trace", "", "record everything sent and received into this JSON lines file")
+	hexDump := flag.Bool("hex", false, "trace command: print a hex dump of every chunk")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	if flag.Arg(0) == "trace" {
+		err = showTrace(flag.Args()[1:], *hexDump)
+	} else {
+		err = run(*port, uint32(*baud), *fake, *traceFile, flag.Arg(0), flag.Args()[1:])
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "makcu:", err)
+		os.Exit(1)
+	}
+}
+
+func run(port string, baud uint32, fake bool, traceFile, name string, rawArgs []string) (err error) {
+	var cmd *subcommand
+	for i := range generated {
+		if generated[i].name == name {
+			cmd = &generated[i]
+		}
+	}
+
+	if cmd == nil && name != "version" {
+		return fmt.Errorf("unknown command %q (run with -h for the list)", name)
+	}
+
+	var args []int
+	if cmd != nil {
+		if len(rawArgs) != len(cmd.args) {
+			return fmt.Errorf("%s takes %d arguments (%v), got %d", name, len(cmd.args), cmd.args, len(rawArgs))
+		}
+
+		for i, a := range rawArgs {
+			v, err := strconv.Atoi(a)
+			if err != nil {
+				return fmt.Errorf("%s: %s must be an integer, got %q", name, cmd.args[i], a)
+			}
+			args = append(args, v)
+		}
+	}
+
+	var dev *makcufake.Device
+	var conn *makcu.MakcuHandle
+	if fake {
+		dev = makcufake.New()
+		conn = makcu.Open(dev)
+	} else {
+		if port == "" {
+			p, err := makcu.Find()
+			if err != nil {
+				return err
+			}
+			port = p
+		}
+
+		c, err := makcu.Connect(port, baud)
+		if err != nil {
+			return err
+		}
+		conn = c
+	}
+	defer conn.Close()
+
+	if traceFile != "" {
+		trace := makcu.NewTrace(0)
+		conn.SetTrace(trace)
+		defer func() {
+			if exportErr := trace.ExportJSONL(traceFile); err == nil {
+				err = exportErr
+			}
+		}()
+	}
+
+	if cmd == nil {
+		v, err := conn.Version()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s (%s)\n", v, conn.Capabilities())
+		return nil
+	}
+
+	if cmd.query != nil {
+		v, err := cmd.query(conn)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(v)
+		return nil
+	}
+
+	if err := cmd.run(conn, args); err != nil {
+		return err
+	}
+
+	if dev != nil {
+		for _, c := range dev.Calls() {
+			fmt.Println(c)
+		}
+	}
+
+	return nil
+}
+
+// 🐱 Prints a trace file, decoded like Trace.Text or as a hex dump
+func showTrace(args []string, hexDump bool) error {
+	if len(args) != 1 {
+		return fmt.Errorf("trace takes the file to print, got %d arguments", len(args))
+	}
+
+	trace, err := makcu.ImportTraceJSONL(args[0])
+	if err != nil {
+		return err
+	}
+
+	if hexDump {
+		fmt.Print(trace.HexDump())
+	} else {
+		fmt.Print(trace.Text())
+	}
+
+	return nil
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "usage: makcu [flags] <command> [args...]\n\nflags:\n")
+	flag.PrintDefaults()
+
+	fmt.Fprintf(out, "\ncommands:\n  %-14s prints the firmware version and capabilities\n", "version")
+	fmt.Fprintf(out, "  %-14s [file] prints a trace recorded with -trace\n", "trace")
+	for _, c := range generated {
+		fmt.Fprintf(out, "  %-14s %v %s\n", c.name, c.args, c.doc)
+	}
+}
@@ -0,0 +1,136 @@
+package makcu
+
+import (
+	"fmt"
+	"time"
+)
+
+// 🐱 Counters for the move coalescer
+type CoalesceStats struct {
+	Moves  uint64 // MoveMouse calls that went through the coalescer
+	Sent   uint64 // km.move commands it actually sent
+	Merged uint64 // calls that were folded into another one (or cancelled out) instead of being sent
+}
+
+// Turns on move coalescing: relative moves made within one tick are added up and sent as a single km.move.
+// The total displacement is always kept, and a pending move is sent before any other command (button, wheel, ...)
+// so nothing is ever reordered. Use it when something calls MoveMouse faster than the serial link can keep up.
+func (m *MakcuHandle) EnableCoalescing(tick time.Duration) error {
+	if m == nil {
+		return fmt.Errorf("EnableCoalescing: %w", ErrNotConnected)
+	}
+
+	if tick <= 0 {
+		return fmt.Errorf("EnableCoalescing: tick must be positive, got %v", tick)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.coalesceTick = tick
+	return nil
+}
+
+// 🐱 Sends whatever move is pending and goes back to sending every MoveMouse as is
+func (m *MakcuHandle) DisableCoalescing() error {
+	if m == nil {
+		return fmt.Errorf("DisableCoalescing: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.coalesceTick = 0
+	if err := m.flushMoveLocked(); err != nil {
+		return fmt.Errorf("DisableCoalescing: %w", err)
+	}
+
+	return nil
+}
+
+// 🐱 Returns a snapshot of the coalescer counters
+func (m *MakcuHandle) CoalesceStats() CoalesceStats {
+	if m == nil {
+		return CoalesceStats{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.coalesceStats
+}
+
+// 🐱🐱🐱 Cat coalescing! 🐱🐱🐱
+
+// 🐱 Relative move, going through the coalescer when it is on
+func (m *MakcuHandle) move(x, y int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.splitMoves {
+		if err := checkMove("MoveMouse", x, y); err != nil {
+			return err
+		}
+	}
+
+	if m.coalesceTick <= 0 {
+		m.cmdBuf = appendMoves(m.cmdBuf[:0], x, y)
+		return m.execLocked(m.cmdBuf)
+	}
+
+	if err := m.flushErr; err != nil {
+		m.flushErr = nil
+		return err
+	}
+
+	m.coalesceStats.Moves++
+	m.moveX += x
+	m.moveY += y
+
+	if m.movePending {
+		m.coalesceStats.Merged++
+		return nil
+	}
+
+	m.movePending = true
+	m.coalesceTimer = time.AfterFunc(m.coalesceTick, m.timedMoveFlush)
+	return nil
+}
+
+// 🐱 Sends the pending coalesced move, if there is one
+func (m *MakcuHandle) flushMoveLocked() error {
+	if m.coalesceTimer != nil {
+		m.coalesceTimer.Stop()
+		m.coalesceTimer = nil
+	}
+
+	if !m.movePending {
+		return nil
+	}
+
+	x, y := m.moveX, m.moveY
+	m.moveX, m.moveY, m.movePending = 0, 0, false
+
+	// the moves cancelled each other out, nothing to send
+	if x == 0 && y == 0 {
+		m.coalesceStats.Merged++
+		return nil
+	}
+
+	// the sum of several in-range moves can be out of range, so this always splits
+	m.coalesceStats.Sent += uint64(pieces(MoveMax, x, y))
+	m.moveBuf = appendMoves(m.moveBuf[:0], x, y)
+	return m.sendLocked(m.moveBuf)
+}
+
+func (m *MakcuHandle) timedMoveFlush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.flushMoveLocked(); err != nil {
+		ErrorPrint("Coalesced move failed: %v", err)
+		m.flushErr = err
+	}
+}
+
+// 🐱🐱🐱 Cat coalesced move! 🐱🐱🐱
@@ -0,0 +1,94 @@
+package makcu
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCoalescing(t *testing.T) {
+	m, dev := openFake(t, "v3.0.0")
+	if err := m.EnableCoalescing(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []func() error{
+		// merged into one move, which has to go out before the click
+		func() error { return m.MoveMouse(10, 0) },
+		func() error { return m.MoveMouse(5, -3) },
+		m.LeftDown,
+		// cancel out, so nothing but the wheel goes out
+		func() error { return m.MoveMouse(7, 7) },
+		func() error { return m.MoveMouse(-7, -7) },
+		func() error { return m.ScrollMouse(1) },
+		// each in range, but the sum isn't, so the flush splits it
+		func() error { return m.MoveMouse(30000, 1) },
+		func() error { return m.MoveMouse(30000, 2) },
+		func() error { return m.MoveMouse(-1, 0) },
+		m.LeftUp,
+		func() error { return m.MoveMouse(0, 4) },
+		m.Flush,
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	want := []string{
+		"Move(15, -3)", "Left(1)",
+		"Wheel(1)",
+		"Move(29999, 1)", "Move(30000, 2)", "Left(0)",
+		"Move(0, 4)",
+	}
+	if got := callNames(dev); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+
+	// the total displacement is kept whatever was merged or split
+	var x, y int
+	for _, c := range dev.Calls() {
+		if c.Fn == "move" {
+			x, y = x+c.Args[0], y+c.Args[1]
+		}
+	}
+	if x != 10+5+7-7+30000+30000-1 || y != -3+7-7+1+2+4 {
+		t.Errorf("moved (%d, %d) in all", x, y)
+	}
+
+	// 8 moves made 3 flushes (the 59999 one sent as 2 commands). 4 moves were folded into another and one flush
+	// cancelled out.
+	want2 := CoalesceStats{Moves: 8, Sent: 4, Merged: 4 + 1}
+	if got := m.CoalesceStats(); got != want2 {
+		t.Errorf("stats = %+v, want %+v", got, want2)
+	}
+}
+
+// 🐱 Turning coalescing off sends what is pending
+func TestDisableCoalescing(t *testing.T) {
+	m, dev := openFake(t, "")
+	if err := m.EnableCoalescing(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := m.MoveMouse(2, -1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := dev.Calls(); len(got) != 0 {
+		t.Fatalf("sent %v before the tick", got)
+	}
+
+	if err := m.DisableCoalescing(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MoveMouse(1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Move(6, -3)", "Move(1, 1)"}
+	if got := callNames(dev); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,637 @@
+// Code generated by cmdgen from commands.json. DO NOT EDIT.
+
+package makcu
+
+import "fmt"
+
+// 🐱 Oldest firmware each capability is available on
+var capabilityRegistry = []struct {
+	cap                 Capability
+	major, minor, patch int
+}{
+	{CapCurveMove, 3, 0, 0},
+	{CapSideButtons, 3, 0, 0},
+	{CapLock, 3, 0, 0},
+	{CapCatch, 3, 0, 0},
+	{CapButtonStream, 3, 0, 0},
+	{CapBinaryFrames, 3, 0, 0},
+	{CapKeyboard, 3, 5, 0},
+}
+
+// 🐱 Firmware functions that set an absolute state, so sending them twice does no harm
+var idempotentCommands = map[string]bool{
+	"left":     true,
+	"right":    true,
+	"middle":   true,
+	"side1":    true,
+	"side2":    true,
+	"buttons":  true,
+	"catch_xy": true,
+	"lock_mx":  true,
+	"lock_my":  true,
+	"lock_ml":  true,
+	"lock_mr":  true,
+	"lock_mm":  true,
+	"lock_ms1": true,
+	"lock_ms2": true,
+	"lock_mw":  true,
+	"down":     true,
+	"up":       true,
+}
+
+// KmLeft sends km.left(state). Sets the left button state (1 = down, 0 = up).
+func (m *MakcuHandle) KmLeft(state int) error {
+	if m == nil {
+		return fmt.Errorf("KmLeft: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLeft(state); err != nil {
+		return err
+	}
+
+	return m.call("left", state)
+}
+
+func validateKmLeft(state int) error {
+	if err := checkRange("KmLeft", "state", state, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmRight sends km.right(state). Sets the right button state (1 = down, 0 = up).
+func (m *MakcuHandle) KmRight(state int) error {
+	if m == nil {
+		return fmt.Errorf("KmRight: %w", ErrNotConnected)
+	}
+
+	if err := validateKmRight(state); err != nil {
+		return err
+	}
+
+	return m.call("right", state)
+}
+
+func validateKmRight(state int) error {
+	if err := checkRange("KmRight", "state", state, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmMiddle sends km.middle(state). Sets the middle button state (1 = down, 0 = up).
+func (m *MakcuHandle) KmMiddle(state int) error {
+	if m == nil {
+		return fmt.Errorf("KmMiddle: %w", ErrNotConnected)
+	}
+
+	if err := validateKmMiddle(state); err != nil {
+		return err
+	}
+
+	return m.call("middle", state)
+}
+
+func validateKmMiddle(state int) error {
+	if err := checkRange("KmMiddle", "state", state, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmSide1 sends km.side1(state). Sets the first side button (mouse 4, usually back) state (1 = down, 0 = up).
+// Needs firmware 3.0.0 or newer (CapSideButtons).
+func (m *MakcuHandle) KmSide1(state int) error {
+	if m == nil {
+		return fmt.Errorf("KmSide1: %w", ErrNotConnected)
+	}
+
+	if err := validateKmSide1(state); err != nil {
+		return err
+	}
+
+	if err := m.require("KmSide1", CapSideButtons); err != nil {
+		return err
+	}
+
+	return m.call("side1", state)
+}
+
+func validateKmSide1(state int) error {
+	if err := checkRange("KmSide1", "state", state, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmSide2 sends km.side2(state). Sets the second side button (mouse 5, usually forward) state (1 = down, 0 = up).
+// Needs firmware 3.0.0 or newer (CapSideButtons).
+func (m *MakcuHandle) KmSide2(state int) error {
+	if m == nil {
+		return fmt.Errorf("KmSide2: %w", ErrNotConnected)
+	}
+
+	if err := validateKmSide2(state); err != nil {
+		return err
+	}
+
+	if err := m.require("KmSide2", CapSideButtons); err != nil {
+		return err
+	}
+
+	return m.call("side2", state)
+}
+
+func validateKmSide2(state int) error {
+	if err := checkRange("KmSide2", "state", state, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLeftState sends km.left() and returns what the MAKCU answers. Reports whether the left button is held down (1) or not (0), by the user or the host.
+func (m *MakcuHandle) KmLeftState() (int, error) {
+	if m == nil {
+		return 0, fmt.Errorf("KmLeftState: %w", ErrNotConnected)
+	}
+
+	return m.query("KmLeftState", "left")
+}
+
+// KmRightState sends km.right() and returns what the MAKCU answers. Reports whether the right button is held down (1) or not (0), by the user or the host.
+func (m *MakcuHandle) KmRightState() (int, error) {
+	if m == nil {
+		return 0, fmt.Errorf("KmRightState: %w", ErrNotConnected)
+	}
+
+	return m.query("KmRightState", "right")
+}
+
+// KmMiddleState sends km.middle() and returns what the MAKCU answers. Reports whether the middle button is held down (1) or not (0), by the user or the host.
+func (m *MakcuHandle) KmMiddleState() (int, error) {
+	if m == nil {
+		return 0, fmt.Errorf("KmMiddleState: %w", ErrNotConnected)
+	}
+
+	return m.query("KmMiddleState", "middle")
+}
+
+// KmSide1State sends km.side1() and returns what the MAKCU answers. Reports whether the first side button is held down (1) or not (0), by the user or the host.
+// Needs firmware 3.0.0 or newer (CapSideButtons).
+func (m *MakcuHandle) KmSide1State() (int, error) {
+	if m == nil {
+		return 0, fmt.Errorf("KmSide1State: %w", ErrNotConnected)
+	}
+
+	if err := m.require("KmSide1State", CapSideButtons); err != nil {
+		return 0, err
+	}
+
+	return m.query("KmSide1State", "side1")
+}
+
+// KmSide2State sends km.side2() and returns what the MAKCU answers. Reports whether the second side button is held down (1) or not (0), by the user or the host.
+// Needs firmware 3.0.0 or newer (CapSideButtons).
+func (m *MakcuHandle) KmSide2State() (int, error) {
+	if m == nil {
+		return 0, fmt.Errorf("KmSide2State: %w", ErrNotConnected)
+	}
+
+	if err := m.require("KmSide2State", CapSideButtons); err != nil {
+		return 0, err
+	}
+
+	return m.query("KmSide2State", "side2")
+}
+
+// KmButtonStream sends km.buttons(enable). Turns streaming of the physical button state on (1) or off (0). While on, the MAKCU sends km. followed by the button mask byte whenever it changes.
+// Needs firmware 3.0.0 or newer (CapButtonStream).
+func (m *MakcuHandle) KmButtonStream(enable int) error {
+	if m == nil {
+		return fmt.Errorf("KmButtonStream: %w", ErrNotConnected)
+	}
+
+	if err := validateKmButtonStream(enable); err != nil {
+		return err
+	}
+
+	if err := m.require("KmButtonStream", CapButtonStream); err != nil {
+		return err
+	}
+
+	return m.call("buttons", enable)
+}
+
+func validateKmButtonStream(enable int) error {
+	if err := checkRange("KmButtonStream", "enable", enable, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmMotionStream sends km.catch_xy(enable). Turns streaming of the physical mouse movement on (1) or off (0). While on, every report from the mouse is sent as a binary motion frame.
+// Needs firmware 3.0.0 or newer (CapCatch).
+func (m *MakcuHandle) KmMotionStream(enable int) error {
+	if m == nil {
+		return fmt.Errorf("KmMotionStream: %w", ErrNotConnected)
+	}
+
+	if err := validateKmMotionStream(enable); err != nil {
+		return err
+	}
+
+	if err := m.require("KmMotionStream", CapCatch); err != nil {
+		return err
+	}
+
+	return m.call("catch_xy", enable)
+}
+
+func validateKmMotionStream(enable int) error {
+	if err := checkRange("KmMotionStream", "enable", enable, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLockX sends km.lock_mx(locked). Blocks (1) or unblocks (0) the physical mouse's horizontal movement from reaching the host. Commands from this library still go through.
+// Needs firmware 3.0.0 or newer (CapLock).
+func (m *MakcuHandle) KmLockX(locked int) error {
+	if m == nil {
+		return fmt.Errorf("KmLockX: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLockX(locked); err != nil {
+		return err
+	}
+
+	if err := m.require("KmLockX", CapLock); err != nil {
+		return err
+	}
+
+	return m.call("lock_mx", locked)
+}
+
+func validateKmLockX(locked int) error {
+	if err := checkRange("KmLockX", "locked", locked, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLockY sends km.lock_my(locked). Blocks (1) or unblocks (0) the physical mouse's vertical movement from reaching the host. Commands from this library still go through.
+// Needs firmware 3.0.0 or newer (CapLock).
+func (m *MakcuHandle) KmLockY(locked int) error {
+	if m == nil {
+		return fmt.Errorf("KmLockY: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLockY(locked); err != nil {
+		return err
+	}
+
+	if err := m.require("KmLockY", CapLock); err != nil {
+		return err
+	}
+
+	return m.call("lock_my", locked)
+}
+
+func validateKmLockY(locked int) error {
+	if err := checkRange("KmLockY", "locked", locked, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLockLeft sends km.lock_ml(locked). Blocks (1) or unblocks (0) the physical mouse's left button from reaching the host. Commands from this library still go through.
+// Needs firmware 3.0.0 or newer (CapLock).
+func (m *MakcuHandle) KmLockLeft(locked int) error {
+	if m == nil {
+		return fmt.Errorf("KmLockLeft: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLockLeft(locked); err != nil {
+		return err
+	}
+
+	if err := m.require("KmLockLeft", CapLock); err != nil {
+		return err
+	}
+
+	return m.call("lock_ml", locked)
+}
+
+func validateKmLockLeft(locked int) error {
+	if err := checkRange("KmLockLeft", "locked", locked, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLockRight sends km.lock_mr(locked). Blocks (1) or unblocks (0) the physical mouse's right button from reaching the host. Commands from this library still go through.
+// Needs firmware 3.0.0 or newer (CapLock).
+func (m *MakcuHandle) KmLockRight(locked int) error {
+	if m == nil {
+		return fmt.Errorf("KmLockRight: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLockRight(locked); err != nil {
+		return err
+	}
+
+	if err := m.require("KmLockRight", CapLock); err != nil {
+		return err
+	}
+
+	return m.call("lock_mr", locked)
+}
+
+func validateKmLockRight(locked int) error {
+	if err := checkRange("KmLockRight", "locked", locked, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLockMiddle sends km.lock_mm(locked). Blocks (1) or unblocks (0) the physical mouse's middle button from reaching the host. Commands from this library still go through.
+// Needs firmware 3.0.0 or newer (CapLock).
+func (m *MakcuHandle) KmLockMiddle(locked int) error {
+	if m == nil {
+		return fmt.Errorf("KmLockMiddle: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLockMiddle(locked); err != nil {
+		return err
+	}
+
+	if err := m.require("KmLockMiddle", CapLock); err != nil {
+		return err
+	}
+
+	return m.call("lock_mm", locked)
+}
+
+func validateKmLockMiddle(locked int) error {
+	if err := checkRange("KmLockMiddle", "locked", locked, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLockSide1 sends km.lock_ms1(locked). Blocks (1) or unblocks (0) the physical mouse's first side button from reaching the host. Commands from this library still go through.
+// Needs firmware 3.0.0 or newer (CapLock).
+func (m *MakcuHandle) KmLockSide1(locked int) error {
+	if m == nil {
+		return fmt.Errorf("KmLockSide1: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLockSide1(locked); err != nil {
+		return err
+	}
+
+	if err := m.require("KmLockSide1", CapLock); err != nil {
+		return err
+	}
+
+	return m.call("lock_ms1", locked)
+}
+
+func validateKmLockSide1(locked int) error {
+	if err := checkRange("KmLockSide1", "locked", locked, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLockSide2 sends km.lock_ms2(locked). Blocks (1) or unblocks (0) the physical mouse's second side button from reaching the host. Commands from this library still go through.
+// Needs firmware 3.0.0 or newer (CapLock).
+func (m *MakcuHandle) KmLockSide2(locked int) error {
+	if m == nil {
+		return fmt.Errorf("KmLockSide2: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLockSide2(locked); err != nil {
+		return err
+	}
+
+	if err := m.require("KmLockSide2", CapLock); err != nil {
+		return err
+	}
+
+	return m.call("lock_ms2", locked)
+}
+
+func validateKmLockSide2(locked int) error {
+	if err := checkRange("KmLockSide2", "locked", locked, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmLockWheel sends km.lock_mw(locked). Blocks (1) or unblocks (0) the physical mouse's wheel from reaching the host. Commands from this library still go through.
+// Needs firmware 3.0.0 or newer (CapLock).
+func (m *MakcuHandle) KmLockWheel(locked int) error {
+	if m == nil {
+		return fmt.Errorf("KmLockWheel: %w", ErrNotConnected)
+	}
+
+	if err := validateKmLockWheel(locked); err != nil {
+		return err
+	}
+
+	if err := m.require("KmLockWheel", CapLock); err != nil {
+		return err
+	}
+
+	return m.call("lock_mw", locked)
+}
+
+func validateKmLockWheel(locked int) error {
+	if err := checkRange("KmLockWheel", "locked", locked, 0, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmKeyDown sends km.down(key). Holds down the keyboard key with HID usage code key.
+// Needs firmware 3.5.0 or newer (CapKeyboard).
+func (m *MakcuHandle) KmKeyDown(key int) error {
+	if m == nil {
+		return fmt.Errorf("KmKeyDown: %w", ErrNotConnected)
+	}
+
+	if err := validateKmKeyDown(key); err != nil {
+		return err
+	}
+
+	if err := m.require("KmKeyDown", CapKeyboard); err != nil {
+		return err
+	}
+
+	return m.call("down", key)
+}
+
+func validateKmKeyDown(key int) error {
+	if err := checkRange("KmKeyDown", "key", key, 0, 255); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmKeyUp sends km.up(key). Releases the keyboard key with HID usage code key.
+// Needs firmware 3.5.0 or newer (CapKeyboard).
+func (m *MakcuHandle) KmKeyUp(key int) error {
+	if m == nil {
+		return fmt.Errorf("KmKeyUp: %w", ErrNotConnected)
+	}
+
+	if err := validateKmKeyUp(key); err != nil {
+		return err
+	}
+
+	if err := m.require("KmKeyUp", CapKeyboard); err != nil {
+		return err
+	}
+
+	return m.call("up", key)
+}
+
+func validateKmKeyUp(key int) error {
+	if err := checkRange("KmKeyUp", "key", key, 0, 255); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmWheel sends km.wheel(amount). Scrolls the wheel by amount notches (positive is up).
+func (m *MakcuHandle) KmWheel(amount int) error {
+	if m == nil {
+		return fmt.Errorf("KmWheel: %w", ErrNotConnected)
+	}
+
+	if err := validateKmWheel(amount); err != nil {
+		return err
+	}
+
+	return m.call("wheel", amount)
+}
+
+func validateKmWheel(amount int) error {
+	if err := checkRange("KmWheel", "amount", amount, -127, 127); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmMove sends km.move(x, y). Moves the cursor by (x, y).
+func (m *MakcuHandle) KmMove(x, y int) error {
+	if m == nil {
+		return fmt.Errorf("KmMove: %w", ErrNotConnected)
+	}
+
+	if err := validateKmMove(x, y); err != nil {
+		return err
+	}
+
+	return m.call("move", x, y)
+}
+
+func validateKmMove(x, y int) error {
+	if err := checkRange("KmMove", "x", x, -32767, 32767); err != nil {
+		return err
+	}
+	if err := checkRange("KmMove", "y", y, -32767, 32767); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmMoveSegments sends km.move(x, y, segments). Moves the cursor by (x, y) in a straight line split into segments steps.
+// Needs firmware 3.0.0 or newer (CapCurveMove).
+func (m *MakcuHandle) KmMoveSegments(x, y, segments int) error {
+	if m == nil {
+		return fmt.Errorf("KmMoveSegments: %w", ErrNotConnected)
+	}
+
+	if err := validateKmMoveSegments(x, y, segments); err != nil {
+		return err
+	}
+
+	if err := m.require("KmMoveSegments", CapCurveMove); err != nil {
+		return err
+	}
+
+	return m.call("move", x, y, segments)
+}
+
+func validateKmMoveSegments(x, y, segments int) error {
+	if err := checkRange("KmMoveSegments", "x", x, -32767, 32767); err != nil {
+		return err
+	}
+	if err := checkRange("KmMoveSegments", "y", y, -32767, 32767); err != nil {
+		return err
+	}
+	if err := checkRange("KmMoveSegments", "segments", segments, 1, 32767); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// KmMoveBezier sends km.move(x, y, segments, cx, cy). Moves the cursor by (x, y) along a quadratic Bezier curve through the control point (cx, cy), in segments steps.
+// Needs firmware 3.0.0 or newer (CapCurveMove).
+func (m *MakcuHandle) KmMoveBezier(x, y, segments, cx, cy int) error {
+	if m == nil {
+		return fmt.Errorf("KmMoveBezier: %w", ErrNotConnected)
+	}
+
+	if err := validateKmMoveBezier(x, y, segments, cx, cy); err != nil {
+		return err
+	}
+
+	if err := m.require("KmMoveBezier", CapCurveMove); err != nil {
+		return err
+	}
+
+	return m.call("move", x, y, segments, cx, cy)
+}
+
+func validateKmMoveBezier(x, y, segments, cx, cy int) error {
+	if err := checkRange("KmMoveBezier", "x", x, -32767, 32767); err != nil {
+		return err
+	}
+	if err := checkRange("KmMoveBezier", "y", y, -32767, 32767); err != nil {
+		return err
+	}
+	if err := checkRange("KmMoveBezier", "segments", segments, 1, 32767); err != nil {
+		return err
+	}
+	if err := checkRange("KmMoveBezier", "cx", cx, -32767, 32767); err != nil {
+		return err
+	}
+	if err := checkRange("KmMoveBezier", "cy", cy, -32767, 32767); err != nil {
+		return err
+	}
+
+	return nil
+}
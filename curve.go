@@ -0,0 +1,165 @@
+package makcu
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// 🐱 Time between the km.move steps of a host-side curve. The firmware spaces its own segments about 1ms apart.
+var CurveStepInterval = 1 * time.Millisecond
+
+// 🐱 Works out the relative steps the firmware takes for km.move(x, y, segments[, cx, cy]).
+// Without a control point the path is a straight line cut into segments pieces, with one it is the
+// quadratic Bezier from (0, 0) through (cx, cy) to (x, y). Every point is rounded from the exact curve
+// (not from the previous step) so rounding never piles up and the steps always add up to exactly (x, y).
+func curveSteps(x, y, segments int, ctrl ...int) ([][2]int, error) {
+	// the upper bound also keeps a silly segment count from allocating a huge step list
+	if err := checkRange("curveSteps", "segments", segments, 1, MoveMax); err != nil {
+		return nil, err
+	}
+
+	if len(ctrl) != 0 && len(ctrl) != 2 {
+		return nil, fmt.Errorf("curve control point needs 2 values, got %d", len(ctrl))
+	}
+
+	cx, cy := float64(x)/2, float64(y)/2 // midpoint control point == straight line
+	if len(ctrl) == 2 {
+		cx, cy = float64(ctrl[0]), float64(ctrl[1])
+	}
+
+	steps := make([][2]int, 0, segments)
+	prevX, prevY := 0, 0
+	for i := 1; i <= segments; i++ {
+		px, py := x, y
+		if i < segments {
+			t := float64(i) / float64(segments)
+			px = int(math.Round(2*(1-t)*t*cx + t*t*float64(x)))
+			py = int(math.Round(2*(1-t)*t*cy + t*t*float64(y)))
+		}
+
+		steps = append(steps, [2]int{px - prevX, py - prevY})
+		prevX, prevY = px, py
+	}
+
+	return steps, nil
+}
+
+// 🐱 Cuts km.move(x, y, segments[, cx, cy]) into n firmware curves that follow the same path, each small enough
+// to be in range. Bezier pieces get the control point of that stretch of the original curve (de Casteljau),
+// and their end points are rounded from the exact curve so the pieces add up to exactly (x, y). The segments are
+// shared out between the pieces, but every piece gets at least one, so with n > segments the curve has n.
+func appendCurves(dst []byte, n, x, y, segments int, ctrl ...int) []byte {
+	if len(ctrl) == 0 {
+		for i := 0; i < n; i++ {
+			dst = appendCall(dst, "move", share(x, n, i), share(y, n, i), max(1, share(segments, n, i)))
+		}
+
+		return dst
+	}
+
+	fx, fy, cx, cy := float64(x), float64(y), float64(ctrl[0]), float64(ctrl[1])
+	at := func(t float64) (float64, float64) {
+		return 2*(1-t)*t*cx + t*t*fx, 2*(1-t)*t*cy + t*t*fy
+	}
+
+	prevX, prevY := 0, 0
+	for i := 0; i < n; i++ {
+		t0, t1 := float64(i)/float64(n), float64(i+1)/float64(n)
+
+		px, py := x, y
+		if i < n-1 {
+			ex, ey := at(t1)
+			px, py = int(math.Round(ex)), int(math.Round(ey))
+		}
+
+		// control point of the sub curve: start + (t1-t0)/2 * B'(t0)
+		sx, sy := at(t0)
+		dx := 2*(1-t0)*cx + 2*t0*(fx-cx)
+		dy := 2*(1-t0)*cy + 2*t0*(fy-cy)
+		qx := int(math.Round(sx+(t1-t0)/2*dx)) - prevX
+		qy := int(math.Round(sy+(t1-t0)/2*dy)) - prevY
+
+		dst = appendCall(dst, "move", px-prevX, py-prevY, max(1, share(segments, n, i)), qx, qy)
+		prevX, prevY = px, py
+	}
+
+	return dst
+}
+
+// 🐱🐱🐱 Cat curve math! 🐱🐱🐱
+
+// 🐱 Sends a firmware curve, checking its arguments and splitting it into several curves when SetSplitMoves is on
+func (m *MakcuHandle) curve(x, y int, params ...int) error {
+	const op = "MoveMouseWithCurve"
+
+	segments, ctrl := params[0], params[1:]
+	if err := checkRange(op, "segments", segments, 1, MoveMax); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// only x, y and the control point have to fit, x-cx / y-cy being too big is fine for a single firmware curve
+	err := checkMove(op, x, y)
+	if err == nil && len(ctrl) == 2 {
+		err = checkRange(op, "cx", ctrl[0], MoveMin, MoveMax)
+		if err == nil {
+			err = checkRange(op, "cy", ctrl[1], MoveMin, MoveMax)
+		}
+	}
+
+	if err == nil {
+		// x, y and the curve params laid out on the stack so encoding doesn't allocate
+		var args [5]int
+		args[0], args[1] = x, y
+		k := 2 + copy(args[2:], params)
+
+		return m.callLocked("move", args[:k]...)
+	}
+
+	if !m.splitMoves {
+		return err
+	}
+
+	n := pieces(MoveMax, x, y)
+	if len(ctrl) == 2 {
+		// a piece covers 1/n of each leg of the control polygon, so its control point is up to one of those away
+		// and its end up to two. Rounding the ends from the exact curve can add one more.
+		cx, cy := ctrl[0], ctrl[1]
+		n = pieces(MoveMax-1, x, y, 2*cx, 2*cy, 2*(x-cx), 2*(y-cy))
+	}
+
+	m.cmdBuf = appendCurves(m.cmdBuf[:0], n, x, y, segments, ctrl...)
+	return m.execLocked(m.cmdBuf)
+}
+
+// 🐱 Plays a curved move as a series of plain km.move commands, for firmware without CapCurveMove
+func (m *MakcuHandle) emulateCurve(x, y int, params ...int) error {
+	steps, err := curveSteps(x, y, params[0], params[1:]...)
+	if err != nil {
+		return fmt.Errorf("MoveMouseWithCurve: %w", err)
+	}
+
+	DebugPrint("Emulating curve to (%d, %d) in %d steps (firmware %s)", x, y, len(steps), m.Firmware)
+
+	for i, step := range steps {
+		if i > 0 && CurveStepInterval > 0 {
+			time.Sleep(CurveStepInterval)
+		}
+
+		// rounding can leave a step with nothing to do, the time still passes so the pacing matches the firmware
+		if step[0] == 0 && step[1] == 0 {
+			continue
+		}
+
+		if err := m.MoveMouse(step[0], step[1]); err != nil {
+			return fmt.Errorf("MoveMouseWithCurve: emulated step %d/%d: %w", i+1, len(steps), err)
+		}
+	}
+
+	return nil
+}
+
+// 🐱🐱🐱 Cat curve emulation! 🐱🐱🐱
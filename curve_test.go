@@ -0,0 +1,110 @@
+package makcu
+
+import "testing"
+
+var curveCases = []struct {
+	name     string
+	x, y     int
+	segments int
+	ctrl     []int
+}{
+	{"line", 100, 50, 10, nil},
+	{"line negative", -100, -37, 7, nil},
+	{"line shorter than segments", 3, -2, 20, nil},
+	{"single segment", -5, 9, 1, nil},
+	{"bezier", 200, 0, 20, []int{100, 100}},
+	{"bezier negative", -150, -80, 13, []int{-20, 60}},
+	{"bezier past the end", 10, 10, 8, []int{-300, 400}},
+}
+
+func TestCurveSteps(t *testing.T) {
+	for _, tc := range curveCases {
+		t.Run(tc.name, func(t *testing.T) {
+			steps, err := curveSteps(tc.x, tc.y, tc.segments, tc.ctrl...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(steps) != tc.segments {
+				t.Errorf("got %d steps, want %d", len(steps), tc.segments)
+			}
+
+			sx, sy := 0, 0
+			for _, s := range steps {
+				sx, sy = sx+s[0], sy+s[1]
+			}
+			if sx != tc.x || sy != tc.y {
+				t.Errorf("steps add up to (%d, %d), want (%d, %d)", sx, sy, tc.x, tc.y)
+			}
+		})
+	}
+}
+
+func TestCurveStepsBadArgs(t *testing.T) {
+	for _, segments := range []int{0, -1, MoveMax + 1} {
+		if _, err := curveSteps(10, 10, segments); err == nil {
+			t.Errorf("segments %d: no error", segments)
+		}
+	}
+
+	if _, err := curveSteps(10, 10, 5, 1); err == nil {
+		t.Error("one control value: no error")
+	}
+}
+
+// 🐱 Firmware before 3.0.0 has no curves, so the library walks the path with plain km.move steps
+func TestEmulateCurve(t *testing.T) {
+	old := CurveStepInterval
+	CurveStepInterval = 0
+	t.Cleanup(func() { CurveStepInterval = old })
+
+	for _, tc := range curveCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, dev := openFake(t, "v2.9.0")
+			if m.Supports(CapCurveMove) {
+				t.Fatal("v2.9.0 should not have CapCurveMove")
+			}
+
+			params := append([]int{tc.segments}, tc.ctrl...)
+			if err := m.MoveMouseWithCurve(tc.x, tc.y, params...); err != nil {
+				t.Fatal(err)
+			}
+
+			steps, _ := curveSteps(tc.x, tc.y, tc.segments, tc.ctrl...)
+			want := 0
+			for _, s := range steps {
+				if s != [2]int{} {
+					want++
+				}
+			}
+
+			calls := dev.Calls()
+			if len(calls) != want {
+				t.Errorf("got %d km.move calls, want %d (the non-empty steps)", len(calls), want)
+			}
+
+			sx, sy := 0, 0
+			for _, c := range calls {
+				if c.Fn != "move" || len(c.Args) != 2 {
+					t.Fatalf("unexpected call %s", c)
+				}
+				sx, sy = sx+c.Args[0], sy+c.Args[1]
+			}
+			if sx != tc.x || sy != tc.y {
+				t.Errorf("moves add up to (%d, %d), want (%d, %d)", sx, sy, tc.x, tc.y)
+			}
+		})
+	}
+}
+
+// 🐱 Firmware with curves gets the curve as a single command
+func TestCurveNative(t *testing.T) {
+	m, dev := openFake(t, "v3.0.0")
+	if err := m.MoveMouseWithCurve(200, 0, 20, 100, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := callNames(dev); len(got) != 1 || got[0] != "MoveBezier(200, 0, 20, 100, 100)" {
+		t.Errorf("calls = %v", got)
+	}
+}
@@ -0,0 +1,346 @@
+package makcu
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// 🐱 Kinds of data the MAKCU sends on its own instead of in reply to a command
+type eventKind int
+
+const (
+	eventButtons eventKind = iota // "km." followed by one raw byte holding the physical button mask (km.buttons streaming)
+	eventMotion                   // binary motion frame with the physical mouse's deltas (km.catch_xy streaming)
+)
+
+// 🐱 One unsolicited message pulled out of the byte stream
+type deviceEvent struct {
+	Kind  eventKind
+	Mask  byte // eventButtons: state of the physical buttons
+	DX    int  // eventMotion: movement and wheel reported by the physical mouse
+	DY    int
+	Wheel int
+	At    time.Time // when the bytes carrying it were read
+}
+
+// 🐱 Prefix of a streamed button state. Replies never have a control byte right after "km.", so it can't be confused with an echo.
+const streamPrefix = "km."
+
+// 🐱 Splits what the MAKCU sends into command replies and stream events. Only ever used with m.rxMu held.
+type demux struct {
+	buf  []byte
+	held []byte // end of the last chunk that might turn out to be the start of a stream packet
+}
+
+// 🐱 Classifies a chunk read from the port. Reply bytes go to reply in order (with the packets cut out),
+// packets go to event. Slices passed to reply are only valid during the call.
+func (d *demux) feed(p []byte, at time.Time, reply func([]byte), event func(deviceEvent)) {
+	d.buf = append(append(d.buf[:0], d.held...), p...)
+	d.held = d.held[:0]
+
+	buf, start := d.buf, 0
+	for i := 0; i < len(buf); i++ {
+		if buf[i] == frameMagic0 {
+			// replies are plain text, so 0xDE can only start a binary frame. One that can't be a motion frame is
+			// left in the replies and scanning goes on from the next byte.
+			if !inboundFrame(buf[i:]) {
+				continue
+			}
+
+			cmd, payload, n, err := parseFrame(buf[i:])
+			if err == errFrameShort {
+				reply(buf[start:i])
+				d.held = append(d.held, buf[i:]...)
+				return
+			}
+
+			if err == nil {
+				reply(buf[start:i])
+				if dx, dy, wheel, ok := parseMotion(payload); ok && cmd == frameMotion {
+					event(deviceEvent{Kind: eventMotion, DX: dx, DY: dy, Wheel: wheel, At: at})
+				}
+				i += n - 1
+				start = i + 1
+			}
+			continue
+		}
+
+		if buf[i] != streamPrefix[0] {
+			continue
+		}
+
+		rest := buf[i:]
+		if len(rest) <= len(streamPrefix) {
+			// could still become "km.<mask>" once the next chunk arrives
+			if streamPrefix[:len(rest)] == string(rest) {
+				reply(buf[start:i])
+				d.held = append(d.held, rest...)
+				return
+			}
+			continue
+		}
+
+		if string(rest[:len(streamPrefix)]) == streamPrefix && rest[len(streamPrefix)] < 0x20 {
+			reply(buf[start:i])
+			event(deviceEvent{Kind: eventButtons, Mask: rest[len(streamPrefix)], At: at})
+			i += len(streamPrefix)
+			start = i + 1
+		}
+	}
+
+	reply(buf[start:])
+}
+
+// 🐱🐱🐱 Cat demux! 🐱🐱🐱
+
+// 🐱 Runs one chunk through the demultiplexer: replies are queued on rxBuf, events go to their subscribers
+func (m *MakcuHandle) route(p []byte, at time.Time) {
+	m.rxMu.Lock()
+	defer m.rxMu.Unlock()
+
+	m.demux.feed(p, at, func(b []byte) {
+		m.rxBuf = append(m.rxBuf, b...)
+	}, func(ev deviceEvent) {
+		for _, ch := range m.subs[ev.Kind] {
+			select {
+			case ch <- ev:
+			default:
+				// a slow consumer loses events rather than stalling the reader and every reply behind it
+			}
+		}
+	})
+
+	if len(m.rxBuf) > maxQueuedReplies {
+		m.trimRepliesLocked()
+	}
+
+	select {
+	case m.rxReady <- struct{}{}:
+	default:
+	}
+}
+
+// 🐱 Most reply bytes kept around while the reader runs. Nobody reads the replies to fire and forget commands,
+// so without a limit they would pile up for as long as a subscription lives.
+const maxQueuedReplies = 64 << 10
+
+// 🐱 Drops the oldest whole replies until rxBuf is back under half the limit
+func (m *MakcuHandle) trimRepliesLocked() {
+	cut := len(m.rxBuf) - maxQueuedReplies/2
+	if i := bytes.Index(m.rxBuf[cut:], []byte(replyPrompt)); i >= 0 {
+		cut += i + len(replyPrompt)
+	}
+
+	m.rxBuf = append(m.rxBuf[:0], m.rxBuf[cut:]...)
+}
+
+// 🐱 Starts the background reader if it isn't running yet. From then on everything read from the port goes
+// through the demultiplexer, and Read only returns reply bytes. The reader exits by itself once the last
+// subscription is gone, and Read goes back to reading the port directly.
+func (m *MakcuHandle) startReader() {
+	m.rxMu.Lock()
+	defer m.rxMu.Unlock()
+
+	if m.reading {
+		return
+	}
+
+	m.reading = true
+	m.stopRd = false
+	m.readErr = nil
+	m.rdDone = make(chan struct{})
+	go m.readLoop(m.rdDone)
+}
+
+// 🐱 Asks the background reader to exit and waits until it has. It finishes the read it is in first, which the
+// COM port's read timeout keeps short (Close closes any other port before this, so its read fails right away).
+func (m *MakcuHandle) stopReader() {
+	m.rxMu.Lock()
+	done := m.rdDone
+	if m.reading {
+		m.stopRd = true
+	}
+	m.rxMu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+}
+
+func (m *MakcuHandle) readLoop(done chan struct{}) {
+	defer close(done)
+
+	buf := make([]byte, 256)
+	for {
+		// checked under the same lock subscribe registers with, so a new subscriber either keeps this reader
+		// going or sees it gone and starts another
+		m.rxMu.Lock()
+		if m.stopRd || len(m.subs) == 0 {
+			m.exitReaderLocked(nil)
+			m.rxMu.Unlock()
+			return
+		}
+		m.rxMu.Unlock()
+
+		n, err := m.readPort(buf)
+		if err != nil {
+			DebugPrint("Reader stopped: %v", err)
+			if m.closed.Load() {
+				// Close got in first, that's not a failure
+				err = nil
+			}
+
+			m.rxMu.Lock()
+			m.exitReaderLocked(err)
+			m.rxMu.Unlock()
+			return
+		}
+
+		if n > 0 {
+			m.route(buf[:n], time.Now())
+		}
+	}
+}
+
+// 🐱 Marks the reader gone, ends every subscription with err (nil after a cancel or Close) and wakes anyone
+// waiting for replies so they go back to reading the port themselves
+func (m *MakcuHandle) exitReaderLocked(err error) {
+	m.reading = false
+	m.readErr = err
+	for kind, chans := range m.subs {
+		for _, ch := range chans {
+			close(ch)
+		}
+		delete(m.subs, kind)
+	}
+
+	select {
+	case m.rxReady <- struct{}{}:
+	default:
+	}
+}
+
+// Returns the read error that ended the event subscriptions, if that is why their channels were closed. It is
+// nil while they run and when they ended because their context was cancelled or the handle was closed. A new
+// subscription clears it.
+//
+//	for ev := range events { ... }
+//	if err := MakcuConn.StreamErr(); err != nil {
+//		log.Println("lost the MAKCU:", err)
+//	}
+func (m *MakcuHandle) StreamErr() error {
+	if m == nil {
+		return fmt.Errorf("StreamErr: %w", ErrNotConnected)
+	}
+
+	m.rxMu.Lock()
+	defer m.rxMu.Unlock()
+
+	return m.readErr
+}
+
+// 🐱 Registers a consumer for one kind of event and starts the reader. cancel unregisters it and closes the channel.
+func (m *MakcuHandle) subscribe(kind eventKind, size int) (events <-chan deviceEvent, cancel func()) {
+	ch := make(chan deviceEvent, size)
+
+	m.rxMu.Lock()
+	if m.subs == nil {
+		m.subs = make(map[eventKind][]chan deviceEvent)
+	}
+	m.subs[kind] = append(m.subs[kind], ch)
+	m.rxMu.Unlock()
+
+	m.startReader()
+
+	return ch, func() {
+		m.rxMu.Lock()
+		defer m.rxMu.Unlock()
+
+		chans := m.subs[kind]
+		for i, c := range chans {
+			if c == ch {
+				m.subs[kind] = append(chans[:i:i], chans[i+1:]...)
+				if len(m.subs[kind]) == 0 {
+					// an empty map lets the reader exit
+					delete(m.subs, kind)
+				}
+				close(ch)
+				return
+			}
+		}
+		// not found: the reader already closed it on its way out
+	}
+}
+
+// 🐱🐱🐱 Cat reader! 🐱🐱🐱
+
+// 🐱 Returns the next reply (everything up to the prompt) the MAKCU sent, reading more from the port as needed
+func (m *MakcuHandle) nextReply(deadline time.Time) (string, error) {
+	prompt := []byte(replyPrompt)
+	for {
+		m.rxMu.Lock()
+		if i := bytes.Index(m.rxBuf, prompt); i >= 0 {
+			reply := string(m.rxBuf[:i])
+			m.rxBuf = append(m.rxBuf[:0], m.rxBuf[i+len(prompt):]...)
+			m.rxMu.Unlock()
+			return reply, nil
+		}
+		reading := m.reading
+		m.rxMu.Unlock()
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return "", ErrTimeout
+		}
+
+		if reading {
+			timer := time.NewTimer(wait)
+			select {
+			case <-m.rxReady:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+
+		n, err := m.readPort(m.rxScratch[:])
+		if err != nil {
+			return "", err
+		}
+
+		if n > 0 {
+			m.route(m.rxScratch[:n], time.Now())
+		}
+	}
+}
+
+// 🐱 Hands out queued reply bytes, waiting up to timeout for some to arrive. Used by Read while the reader runs.
+func (m *MakcuHandle) readReply(buffer []byte, timeout time.Duration) (int, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		m.rxMu.Lock()
+		if len(m.rxBuf) > 0 {
+			n := copy(buffer, m.rxBuf)
+			m.rxBuf = append(m.rxBuf[:0], m.rxBuf[n:]...)
+			m.rxMu.Unlock()
+			return n, nil
+		}
+		reading := m.reading
+		m.rxMu.Unlock()
+
+		wait := time.Until(deadline)
+		if !reading || wait <= 0 {
+			return 0, nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-m.rxReady:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// 🐱🐱🐱 Cat reply queue! 🐱🐱🐱
@@ -0,0 +1,251 @@
+package makcu
+
+import (
+	"context"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// 🐱 Replies with button packets and motion frames wedged in between, like a busy MAKCU sends them
+func interleavedTraffic() (stream []byte, replies string, events []deviceEvent) {
+	motion := appendFrame(nil, frameMotion, []byte{5, 0, 0xFD, 0xFF, 1}) // dx 5, dy -3, wheel 1
+
+	parts := []struct {
+		reply string
+		raw   []byte
+		ev    *deviceEvent
+	}{
+		{reply: "km.move(1, 2)\r\n>>> "},
+		{raw: []byte("km.\x01"), ev: &deviceEvent{Kind: eventButtons, Mask: 1}},
+		{reply: "km.left()\r\n"},
+		{raw: motion, ev: &deviceEvent{Kind: eventMotion, DX: 5, DY: -3, Wheel: 1}},
+		{reply: "1\r\n>>> "},
+		{raw: []byte("km.\x00"), ev: &deviceEvent{Kind: eventButtons, Mask: 0}},
+		{raw: []byte("km.\x1f"), ev: &deviceEvent{Kind: eventButtons, Mask: 0x1f}},
+		{reply: "km.version()\r\nkm.MAKCU v3.5.0\r\n>>> "},
+		{raw: motion, ev: &deviceEvent{Kind: eventMotion, DX: 5, DY: -3, Wheel: 1}},
+	}
+
+	for _, p := range parts {
+		if p.ev != nil {
+			stream = append(stream, p.raw...)
+			events = append(events, *p.ev)
+			continue
+		}
+		stream = append(stream, p.reply...)
+		replies += p.reply
+	}
+
+	return stream, replies, events
+}
+
+// 🐱 Feeds chunks through a fresh demux and collects what comes out
+func runDemux(chunks [][]byte) (string, []deviceEvent) {
+	var d demux
+	var replies []byte
+	var events []deviceEvent
+	for _, c := range chunks {
+		d.feed(c, time.Time{}, func(b []byte) {
+			replies = append(replies, b...)
+		}, func(ev deviceEvent) {
+			events = append(events, ev)
+		})
+	}
+
+	return string(replies), events
+}
+
+func TestDemuxInterleaved(t *testing.T) {
+	stream, wantReplies, wantEvents := interleavedTraffic()
+
+	check := func(name string, chunks [][]byte) {
+		t.Helper()
+
+		replies, events := runDemux(chunks)
+		if replies != wantReplies {
+			t.Errorf("%s: replies = %q, want %q", name, replies, wantReplies)
+		}
+		if !reflect.DeepEqual(events, wantEvents) {
+			t.Errorf("%s: events = %+v, want %+v", name, events, wantEvents)
+		}
+	}
+
+	check("one chunk", [][]byte{stream})
+
+	// every place a read could cut the stream, including inside "km." and inside a frame
+	for i := 1; i < len(stream); i++ {
+		check("split at "+strconv.Itoa(i), [][]byte{stream[:i], stream[i:]})
+	}
+
+	var bytewise [][]byte
+	for i := range stream {
+		bytewise = append(bytewise, stream[i:i+1])
+	}
+	check("byte by byte", bytewise)
+}
+
+// 🐱 Replies that merely contain "km." (every echo does) must come through untouched
+func TestDemuxEchoesArentPackets(t *testing.T) {
+	in := "km.side1(1)\r\n>>> km.move(-5, 7)\r\n>>> km.catch_xy(1)\r\n>>> "
+	replies, events := runDemux([][]byte{[]byte(in)})
+	if replies != in || len(events) != 0 {
+		t.Errorf("replies = %q, events = %v", replies, events)
+	}
+}
+
+// 🐱 A 0xDE 0xAD that can't start a motion frame mustn't swallow the replies after it while waiting for its
+// "payload", and a real frame right behind it still counts
+func TestDemuxBogusFrameHeader(t *testing.T) {
+	motion := []byte{0xDE, 0xAD, 0x06, 0x00, frameMotion, 0x03, 0x00, 0xFE, 0xFF, 0x01}
+	cases := map[string][]byte{
+		"huge length": {0xDE, 0xAD, 0xFF, 0x7F},
+		"zero length": {0xDE, 0xAD, 0x00, 0x00},
+		"other cmd":   {0xDE, 0xAD, 0x06, 0x00, 0xA5},
+	}
+
+	for name, header := range cases {
+		reply := "km.move(1, 2)\r\n>>> "
+		replies, events := runDemux([][]byte{header, []byte(reply), motion})
+		if replies != string(header)+reply {
+			t.Errorf("%s: replies = %q", name, replies)
+		}
+		if len(events) != 1 || events[0].DX != 3 || events[0].DY != -2 || events[0].Wheel != 1 {
+			t.Errorf("%s: events = %v", name, events)
+		}
+	}
+}
+
+// 🐱 A net.Conn's Read has no timeout, so Close has to close it to get the reader out instead of waiting for it
+func TestCloseNetPipe(t *testing.T) {
+	conn, peer := net.Pipe()
+	defer peer.Close()
+	go io.Copy(io.Discard, peer)
+
+	m := Open(conn)
+	events, err := m.SubscribeButtons(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- m.Close() }()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close hung on a blocked Read")
+	}
+
+	for range events {
+	}
+
+	if err := m.StreamErr(); err != nil {
+		t.Errorf("StreamErr after Close = %v, want nil", err)
+	}
+}
+
+// 🐱 Closing a handle with a live subscription must stop the reader before the port goes away (run with -race)
+func TestCloseWithSubscription(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		m, dev := openFake(t, "")
+
+		events, err := m.SubscribeButtons(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dev.PhysicalButtons(1)
+		if ev := <-events; ev.Button != ButtonLeft || !ev.Pressed {
+			t.Fatalf("got %v, want left down", ev)
+		}
+
+		go m.MoveMouse(1, 1)
+		dev.PhysicalButtons(0)
+
+		if err := m.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		for range events {
+		}
+
+		if _, err := m.Read(make([]byte, 8)); err == nil {
+			t.Error("Read after Close: no error")
+		}
+	}
+}
+
+// 🐱 Queries keep working while the reader is busy splitting stream packets out
+func TestQueriesDuringStream(t *testing.T) {
+	m, dev := openFake(t, "")
+
+	events, err := m.SubscribeButtons(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			dev.PhysicalButtons(byte(i & 1))
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		if _, err := m.Buttons(); err != nil {
+			t.Fatalf("Buttons #%d: %v", i+1, err)
+		}
+	}
+	<-done
+
+	n := 0
+	for n < 49 {
+		select {
+		case <-events:
+			n++
+		case <-time.After(time.Second):
+			t.Fatalf("only %d of 49 button events arrived", n)
+		}
+	}
+}
+
+func FuzzDemuxFeed(f *testing.F) {
+	// each recording split where its reads were split
+	for _, rec := range recordedTraffic(f) {
+		at := 0
+		for _, chunk := range rec.rx {
+			at += len(chunk)
+			f.Add(rec.stream, uint16(at))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, split uint16) {
+		wantReplies, wantEvents := runDemux([][]byte{data})
+
+		// however the bytes are cut into reads, the same replies and events come out
+		i := int(split) % (len(data) + 1)
+		replies, events := runDemux([][]byte{data[:i], data[i:]})
+		if replies != wantReplies || !reflect.DeepEqual(events, wantEvents) {
+			t.Fatalf("split at %d: replies %q events %v, whole: replies %q events %v", i, replies, events, wantReplies, wantEvents)
+		}
+
+		// nothing but a partial motion frame (or "km." prefix) is ever held back
+		var d demux
+		d.feed(data, time.Time{}, func([]byte) {}, func(deviceEvent) {})
+		if len(d.held) > frameHeader+motionSize {
+			t.Fatalf("%d bytes held back: % x", len(d.held), d.held)
+		}
+
+		if len(replies) > len(data) {
+			t.Fatalf("%d reply bytes out of %d input bytes", len(replies), len(data))
+		}
+	})
+}
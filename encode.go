@@ -0,0 +1,45 @@
+package makcu
+
+import "strconv"
+
+// 🐱 Appends "km.<fn>(arg, arg, ...)\r" to dst. Nothing is allocated as long as dst has room, which it does once
+// the handle's scratch buffers have grown to fit the longest command.
+func appendCall(dst []byte, fn string, args ...int) []byte {
+	dst = append(dst, "km."...)
+	dst = append(dst, fn...)
+	dst = append(dst, '(')
+	for i, a := range args {
+		if i > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = strconv.AppendInt(dst, int64(a), 10)
+	}
+
+	return append(dst, ")\r"...)
+}
+
+// 🐱 Encodes km.<fn>(args...) into the handle's scratch buffer and sends it
+func (m *MakcuHandle) call(fn string, args ...int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.callLocked(fn, args...)
+}
+
+// 🐱 Same as call with m.mu already held
+func (m *MakcuHandle) callLocked(fn string, args ...int) error {
+	m.cmdBuf = appendCall(m.cmdBuf[:0], fn, args...)
+	return m.execLocked(m.cmdBuf)
+}
+
+// 🐱 Sends km.<fn>(1) and km.<fn>(0) together in one write
+func (m *MakcuHandle) callClick(fn string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cmdBuf = appendCall(m.cmdBuf[:0], fn, 1)
+	m.cmdBuf = appendCall(m.cmdBuf, fn, 0)
+	return m.execLocked(m.cmdBuf)
+}
+
+// 🐱🐱🐱 Cat encoder! 🐱🐱🐱
@@ -0,0 +1,69 @@
+package makcu
+
+import "testing"
+
+// The hot path is measured over countingPort rather than makcufake, which parses every command into strings and
+// would drown out the library's own allocations.
+
+func BenchmarkMoveMouse(b *testing.B) {
+	m := Open(&countingPort{})
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := m.MoveMouse(i%200-100, -7); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLeftClick(b *testing.B) {
+	m := Open(&countingPort{})
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := m.LeftClick(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestHotPathAllocs(t *testing.T) {
+	m := Open(&countingPort{})
+
+	for _, tc := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"MoveMouse", func() error { return m.MoveMouse(-32767, 32767) }},
+		{"MoveMouseWithCurve", func() error { return m.MoveMouseWithCurve(100, -100, 20, 50, 50) }},
+		{"LeftClick", m.LeftClick},
+		{"Tap", func() error { return m.Tap(ButtonSide2, 0) }},
+		{"ScrollMouse", func() error { return m.ScrollMouse(-5) }},
+	} {
+		// the first call grows the scratch buffers, which is allowed to allocate
+		if err := tc.fn(); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		if n := testing.AllocsPerRun(100, func() { _ = tc.fn() }); n != 0 {
+			t.Errorf("%s: %v allocations per call, want 0", tc.name, n)
+		}
+	}
+}
+
+func TestAppendCall(t *testing.T) {
+	for _, tc := range []struct {
+		fn   string
+		args []int
+		want string
+	}{
+		{"version", nil, "km.version()\r"},
+		{"left", []int{1}, "km.left(1)\r"},
+		{"move", []int{-32768, 32767}, "km.move(-32768, 32767)\r"},
+		{"move", []int{1, 2, 3, 4, 5}, "km.move(1, 2, 3, 4, 5)\r"},
+	} {
+		if got := string(appendCall(nil, tc.fn, tc.args...)); got != tc.want {
+			t.Errorf("appendCall(%q, %v) = %q, want %q", tc.fn, tc.args, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package makcu
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 🐱 Errors every function in the package wraps, so callers can check them with errors.Is
+var (
+	ErrNotConnected   = errors.New("MakcuHandle is nil or closed (no device connected)")
+	ErrDeviceNotFound = errors.New("MAKCU device not found")
+	ErrTimeout        = errors.New("timed out waiting for the MAKCU")
+	ErrInvalidButton  = errors.New("invalid mouse button")
+	ErrUnsupported    = errors.New("not supported by this firmware")
+	ErrOutOfRange     = errors.New("argument out of range")
+	ErrLinkBusy       = errors.New("serial link busy, the MAKCU's buffer would overflow")
+)
+
+// 🐱🐱🐱 Cat sentinels! 🐱🐱🐱
+
+// 🐱 The MAKCU answered, but not with what we expected. Reply holds what it actually sent back.
+type DeviceError struct {
+	Op      string
+	Command string
+	Reply   string
+}
+
+func (e *DeviceError) Error() string {
+	return fmt.Sprintf("%s: MAKCU replied %q to %q", e.Op, e.Reply, e.Command)
+}
+
+// 🐱 Says which capability was missing and on what firmware. errors.Is(err, ErrUnsupported) matches it.
+type UnsupportedError struct {
+	Op       string
+	Cap      Capability
+	Firmware FirmwareVersion
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s: %s %s (firmware %s)", e.Op, e.Cap, ErrUnsupported, e.Firmware)
+}
+
+func (e *UnsupportedError) Unwrap() error {
+	return ErrUnsupported
+}
+
+// 🐱 Characters TypeString couldn't type on the chosen layout, each listed once in the order they first appear
+type UnrepresentableError struct {
+	Layout   string
+	Fallback string // name of the UnicodeFallback that couldn't help either, "" without one
+	Chars    []rune
+}
+
+func (e *UnrepresentableError) Error() string {
+	quoted := make([]string, len(e.Chars))
+	for i, r := range e.Chars {
+		quoted[i] = strconv.QuoteRune(r)
+	}
+
+	if e.Fallback != "" {
+		return fmt.Sprintf("TypeString: neither the %s layout nor the %s fallback can type %s", e.Layout, e.Fallback, strings.Join(quoted, ", "))
+	}
+
+	return fmt.Sprintf("TypeString: the %s layout can't type %s", e.Layout, strings.Join(quoted, ", "))
+}
+
+// 🐱🐱🐱 Cat error types! 🐱🐱🐱
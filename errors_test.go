@@ -0,0 +1,123 @@
+package makcu
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// 🐱 Commands that need a live handle, each failing with ErrNotConnected on a nil or closed one
+var handleCalls = []struct {
+	name string
+	fn   func(m *MakcuHandle) error
+}{
+	{"MoveMouse", func(m *MakcuHandle) error { return m.MoveMouse(1, 1) }},
+	{"MoveMouseWithCurve", func(m *MakcuHandle) error { return m.MoveMouseWithCurve(10, 10, 5) }},
+	{"ScrollMouse", func(m *MakcuHandle) error { return m.ScrollMouse(1) }},
+	{"LeftClick", (*MakcuHandle).LeftClick},
+	{"Click", func(m *MakcuHandle) error { return m.Click(MOUSE_BUTTON_LEFT, 0) }},
+	{"Press", func(m *MakcuHandle) error { return m.Press(ButtonRight) }},
+	{"MultiClick", func(m *MakcuHandle) error { return m.MultiClick(ButtonLeft, 2, 0, 0) }},
+	{"SetButtons", func(m *MakcuHandle) error { return m.SetButtons(ButtonLeft.Mask()) }},
+	{"KeyDown", func(m *MakcuHandle) error { return m.KeyDown(KeyA) }},
+	{"KeyCombo", func(m *MakcuHandle) error { return m.KeyCombo(ModCtrl, KeyC, 0) }},
+	{"TypeString", func(m *MakcuHandle) error { return m.TypeString(context.Background(), "hi", LayoutUS) }},
+	{"Lock", func(m *MakcuHandle) error { return m.Lock(LockX) }},
+	{"KmMove", func(m *MakcuHandle) error { return m.KmMove(1, 2) }},
+	{"Batch", func(m *MakcuHandle) error { return m.Batch().Move(1, 1).Flush() }},
+	{"Write", func(m *MakcuHandle) error { _, err := m.Write([]byte("km.left(1)\r")); return err }},
+	{"WriteAck", func(m *MakcuHandle) error { _, err := m.WriteAck([]byte("km.left(1)\r"), time.Second); return err }},
+	{"Read", func(m *MakcuHandle) error { _, err := m.Read(make([]byte, 8)); return err }},
+	{"Version", func(m *MakcuHandle) error { _, err := m.Version(); return err }},
+	{"Buttons", func(m *MakcuHandle) error { _, err := m.Buttons(); return err }},
+	{"IsPressed", func(m *MakcuHandle) error { _, err := m.IsPressed(ButtonLeft); return err }},
+	{"SubscribeButtons", func(m *MakcuHandle) error { _, err := m.SubscribeButtons(context.Background()); return err }},
+	{"SubscribeMotion", func(m *MakcuHandle) error { _, err := m.SubscribeMotion(context.Background()); return err }},
+	{"ChangeBaudRate", func(m *MakcuHandle) error { _, err := ChangeBaudRate(m); return err }},
+	{"Close", (*MakcuHandle).Close},
+}
+
+func TestErrNotConnected(t *testing.T) {
+	var nilHandle *MakcuHandle
+	closed, _ := openFake(t, "")
+	if err := closed.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range handleCalls {
+		for name, m := range map[string]*MakcuHandle{"nil": nilHandle, "closed": closed} {
+			if err := tc.fn(m); !errors.Is(err, ErrNotConnected) {
+				t.Errorf("%s on a %s handle: got %v, want ErrNotConnected", tc.name, name, err)
+			}
+		}
+	}
+
+	// the setters only need the handle itself
+	for name, fn := range map[string]func() error{
+		"SetSplitMoves":      func() error { return nilHandle.SetSplitMoves(true) },
+		"SetChecked":         func() error { return nilHandle.SetChecked(true) },
+		"SetAck":             func() error { return nilHandle.SetAck(time.Second) },
+		"SetAutoFlush":       func() error { return nilHandle.SetAutoFlush(time.Millisecond) },
+		"SetTypingDelay":     func() error { return nilHandle.SetTypingDelay(time.Millisecond) },
+		"SetUnicodeFallback": func() error { return nilHandle.SetUnicodeFallback(nil) },
+		"SetTrace":           func() error { return nilHandle.SetTrace(nil) },
+		"EnableCoalescing":   func() error { return nilHandle.EnableCoalescing(time.Millisecond) },
+		"Flush":              nilHandle.Flush,
+	} {
+		if err := fn(); !errors.Is(err, ErrNotConnected) {
+			t.Errorf("%s on a nil handle: got %v, want ErrNotConnected", name, err)
+		}
+	}
+}
+
+// 🐱 No such port on any machine the tests run on
+func TestConnectNoSuchPort(t *testing.T) {
+	for _, baud := range []uint32{115200, 0} {
+		m, err := Connect("COM250", baud)
+		if err == nil {
+			m.Close()
+			t.Fatalf("Connect(COM250, %d) succeeded", baud)
+		}
+
+		if !errors.Is(err, ErrDeviceNotFound) {
+			t.Errorf("Connect(COM250, %d): got %v, want ErrDeviceNotFound", baud, err)
+		}
+	}
+}
+
+func TestErrorTypes(t *testing.T) {
+	m, dev := openFake(t, "v2.9.0")
+	if err := m.SetChecked(true); err != nil {
+		t.Fatal(err)
+	}
+
+	err := m.Batch().Raw("km.bogus(1)").Flush()
+	var de *DeviceError
+	if !errors.As(err, &de) || de.Command != "km.bogus(1)" || de.Reply == "" {
+		t.Errorf("unknown command: got %v, want a *DeviceError for km.bogus(1)", err)
+	}
+
+	err = m.MoveMouse(MoveMax+1, 0)
+	var re *RangeError
+	if !errors.Is(err, ErrOutOfRange) || !errors.As(err, &re) || re.Arg != "x" || re.Value != MoveMax+1 {
+		t.Errorf("MoveMouse out of range: got %v, want a *RangeError for x", err)
+	}
+
+	err = m.Side1Click()
+	var ue *UnsupportedError
+	if !errors.Is(err, ErrUnsupported) || !errors.As(err, &ue) || ue.Cap != CapSideButtons || ue.Firmware.String() != "v2.9.0" {
+		t.Errorf("side button on v2.9.0: got %v, want an *UnsupportedError", err)
+	}
+
+	// none of the sentinels leak into each other
+	for _, sentinel := range []error{ErrNotConnected, ErrDeviceNotFound, ErrTimeout, ErrInvalidButton, ErrLinkBusy} {
+		if errors.Is(err, sentinel) {
+			t.Errorf("%v also matches %v", err, sentinel)
+		}
+	}
+
+	if rejected := dev.Rejected(); len(rejected) != 1 {
+		t.Errorf("rejected = %v, want just km.bogus(1)", rejected)
+	}
+}
@@ -0,0 +1,219 @@
+package makcu
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 🐱 A physical button going down or up, as reported by the MAKCU's button stream
+type ButtonEvent struct {
+	Button  Button
+	Pressed bool       // true when it went down, false when it came back up
+	Buttons ButtonMask // every button held right after this change
+	At      time.Time  // when the packet carrying it was read from the port
+}
+
+func (e ButtonEvent) String() string {
+	if e.Pressed {
+		return e.Button.String() + " down"
+	}
+
+	return e.Button.String() + " up"
+}
+
+// Streams the user's physical button presses and releases. The MAKCU's button stream is turned on for as long as
+// at least one subscription is alive, and the channel is closed once ctx is done, the handle is closed or reading
+// from the port fails (StreamErr tells which).
+// Buttons that change together in one packet come out in Button order. A consumer that falls far behind loses
+// events instead of holding up command replies.
+//
+//	events, err := MakcuConn.SubscribeButtons(ctx)
+//	for ev := range events {
+//		if ev.Button == makcu.ButtonSide1 && ev.Pressed { ... }
+//	}
+func (m *MakcuHandle) SubscribeButtons(ctx context.Context) (<-chan ButtonEvent, error) {
+	if m == nil {
+		return nil, fmt.Errorf("SubscribeButtons: %w", ErrNotConnected)
+	}
+
+	if err := m.require("SubscribeButtons", CapButtonStream); err != nil {
+		return nil, err
+	}
+
+	events, cancel := m.subscribe(eventButtons, 64)
+	if err := m.streamAcquire(eventButtons); err != nil {
+		cancel()
+		return nil, fmt.Errorf("SubscribeButtons: %w", err)
+	}
+
+	out := make(chan ButtonEvent, 64)
+	go func() {
+		defer close(out)
+		defer cancel()
+		defer m.streamRelease(eventButtons)
+
+		var prev ButtonMask
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+
+				mask := ButtonMask(ev.Mask)
+				changed := prev ^ mask
+				prev = mask
+				for _, b := range AllButtons {
+					if !changed.Has(b) {
+						continue
+					}
+
+					select {
+					case out <- ButtonEvent{Button: b, Pressed: mask.Has(b), Buttons: mask, At: ev.At}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// 🐱🐱🐱 Cat button events! 🐱🐱🐱
+
+// 🐱 One movement report from the user's physical mouse
+type Motion struct {
+	DX, DY int
+	Wheel  int
+	At     time.Time // when the frame carrying it was read from the port
+}
+
+// Streams the user's physical mouse movement as it passes through the MAKCU (its catch feature). Reports arrive
+// through the same reader as command replies and button events, so all three can be used at once. The MAKCU's
+// motion stream is on for as long as at least one subscription is alive, and the channel is closed once ctx is
+// done, the handle is closed or reading fails (see StreamErr). A consumer that falls far behind loses reports
+// instead of holding up replies.
+//
+//	moves, err := MakcuConn.SubscribeMotion(ctx)
+//	for mv := range moves {
+//		fmt.Println(mv.DX, mv.DY)
+//	}
+func (m *MakcuHandle) SubscribeMotion(ctx context.Context) (<-chan Motion, error) {
+	if m == nil {
+		return nil, fmt.Errorf("SubscribeMotion: %w", ErrNotConnected)
+	}
+
+	if err := m.require("SubscribeMotion", CapCatch); err != nil {
+		return nil, err
+	}
+
+	// mice report at up to 8kHz, so leave room for a consumer that blinks
+	events, cancel := m.subscribe(eventMotion, 1024)
+	if err := m.streamAcquire(eventMotion); err != nil {
+		cancel()
+		return nil, fmt.Errorf("SubscribeMotion: %w", err)
+	}
+
+	out := make(chan Motion, 1024)
+	go func() {
+		defer close(out)
+		defer cancel()
+		defer m.streamRelease(eventMotion)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+
+				select {
+				case out <- Motion{DX: ev.DX, DY: ev.DY, Wheel: ev.Wheel, At: ev.At}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// 🐱🐱🐱 Cat motion! 🐱🐱🐱
+
+// 🐱 Turns the firmware stream for kind on or off
+func (m *MakcuHandle) setStream(kind eventKind, on bool) error {
+	state := 0
+	if on {
+		state = 1
+	}
+
+	switch kind {
+	case eventButtons:
+		return m.KmButtonStream(state)
+	case eventMotion:
+		return m.KmMotionStream(state)
+	}
+
+	return fmt.Errorf("no stream for event kind %d", kind)
+}
+
+// 🐱 Counts a new subscriber for kind, turning the stream on if it is the first
+func (m *MakcuHandle) streamAcquire(kind eventKind) error {
+	m.streamMu.Lock()
+	defer m.streamMu.Unlock()
+
+	if m.streams[kind] == 0 {
+		if err := m.setStream(kind, true); err != nil {
+			return err
+		}
+	}
+
+	if m.streams == nil {
+		m.streams = make(map[eventKind]int)
+	}
+	m.streams[kind]++
+	return nil
+}
+
+// 🐱 Drops a subscriber for kind, turning the stream off after the last one
+func (m *MakcuHandle) streamRelease(kind eventKind) {
+	m.streamMu.Lock()
+	defer m.streamMu.Unlock()
+
+	if m.streams[kind] == 0 {
+		return
+	}
+
+	m.streams[kind]--
+	if m.streams[kind] > 0 {
+		return
+	}
+
+	delete(m.streams, kind)
+	if err := m.setStream(kind, false); err != nil {
+		DebugPrint("Failed to turn off stream %d: %v", kind, err)
+	}
+}
+
+// 🐱 Turns every stream off before the port closes so the MAKCU doesn't keep sending into the void
+func (m *MakcuHandle) stopStreams() {
+	m.streamMu.Lock()
+	defer m.streamMu.Unlock()
+
+	for kind := range m.streams {
+		if err := m.setStream(kind, false); err != nil {
+			DebugPrint("Failed to turn off stream %d: %v", kind, err)
+		}
+		delete(m.streams, kind)
+	}
+}
+
+// 🐱🐱🐱 Cat streams! 🐱🐱🐱
@@ -0,0 +1,140 @@
+package makcu
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 🐱 Waits for the background reader to be gone (or running, with want set)
+func waitReader(t *testing.T, m *MakcuHandle, want bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		m.rxMu.Lock()
+		reading := m.reading
+		m.rxMu.Unlock()
+		if reading == want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("reader running = %v after 1s, want %v", !want, want)
+}
+
+func TestSubscribeButtons(t *testing.T) {
+	m, dev := openFake(t, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := m.SubscribeButtons(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev.PhysicalButtons(byte(ButtonLeft.Mask()))
+	dev.PhysicalButtons(byte(ButtonLeft.Mask() | ButtonSide1.Mask()))
+	dev.PhysicalButtons(0)
+
+	var got []string
+	for len(got) < 4 {
+		select {
+		case ev := <-events:
+			got = append(got, ev.String())
+		case <-time.After(time.Second):
+			t.Fatalf("got %v, then nothing", got)
+		}
+	}
+
+	want := []string{"left down", "side1 down", "left up", "side1 up"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+// 🐱 Cancelling the last subscription turns the stream off and stops the reader, so Read reads the port again
+func TestReaderStopsWithLastSubscription(t *testing.T) {
+	m, dev := openFake(t, "")
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	buttons, err := m.SubscribeButtons(ctx1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves, err := m.SubscribeMotion(ctx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitReader(t, m, true)
+
+	cancel1()
+	for range buttons {
+	}
+
+	// the motion subscription still needs it
+	dev.PhysicalMotion(3, -4, 0)
+	if mv := <-moves; mv.DX != 3 || mv.DY != -4 {
+		t.Errorf("motion = %+v", mv)
+	}
+
+	cancel2()
+	for range moves {
+	}
+	waitReader(t, m, false)
+
+	if err := m.StreamErr(); err != nil {
+		t.Errorf("StreamErr after cancel = %v, want nil", err)
+	}
+
+	want := map[string]bool{"ButtonStream(0)": true, "MotionStream(0)": true}
+	for _, c := range callNames(dev) {
+		delete(want, c)
+	}
+	if len(want) != 0 {
+		t.Errorf("streams never turned off: %v", want)
+	}
+
+	// plain reads and queries go straight to the port again
+	if _, err := m.Buttons(); err != nil {
+		t.Fatal(err)
+	}
+
+	// and a new subscription brings the reader back
+	events, err := m.SubscribeButtons(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev.PhysicalButtons(byte(ButtonRight.Mask()))
+	if ev := <-events; ev.Button != ButtonRight || !ev.Pressed {
+		t.Errorf("after resubscribing: %v", ev)
+	}
+}
+
+// 🐱 A dead port ends the subscriptions with an error StreamErr reports, unlike a cancel
+func TestStreamErrOnReadFailure(t *testing.T) {
+	m, dev := openFake(t, "")
+
+	events, err := m.SubscribeButtons(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev.Close() // pulled the cable
+
+	select {
+	case _, ok := <-events:
+		for ok {
+			_, ok = <-events
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscription still open after the port died")
+	}
+
+	if err := m.StreamErr(); err == nil {
+		t.Error("StreamErr = nil after a read failure")
+	}
+}
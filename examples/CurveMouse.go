@@ -11,7 +11,8 @@ func main() {
 	makcu.Debug = false
 	ComPort, _ := makcu.Find()
 
-	MakcuConn, err := makcu.Connect(ComPort, 4000000)
+	// 0 = auto-detect whatever baud rate the MAKCU is currently at (115200 after power on, 4m after ChangeBaudRate)
+	MakcuConn, err := makcu.Connect(ComPort, 0)
 	if err != nil || MakcuConn == nil {
 		fmt.Printf("Error connecting: %v\n", err)
 		fmt.Println("No MAKCU device found or failed to connect. Exiting gracefully. 🐱")
@@ -0,0 +1,35 @@
+package makcu
+
+import (
+	"testing"
+
+	"github.com/nullpkt/Makcu-Go/makcufake"
+)
+
+// 🐱 Opens a handle on a fake MAKCU. A non-empty version is what the fake reports and what the handle gates on.
+func openFake(t testing.TB, version string) (*MakcuHandle, *makcufake.Device) {
+	t.Helper()
+
+	dev := makcufake.New()
+	m := Open(dev)
+	if version != "" {
+		dev.Version = "km.MAKCU " + version
+		if _, err := m.Version(); err != nil {
+			t.Fatalf("Version: %v", err)
+		}
+		dev.Reset()
+	}
+
+	t.Cleanup(func() { m.Close() })
+	return m, dev
+}
+
+// 🐱 The fake's calls as strings, e.g. ["Left(1)", "Left(0)"]
+func callNames(dev *makcufake.Device) []string {
+	var names []string
+	for _, c := range dev.Calls() {
+		names = append(names, c.String())
+	}
+
+	return names
+}
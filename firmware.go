@@ -0,0 +1,194 @@
+package makcu
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 🐱 Firmware version of the MAKCU, parsed from the km.version() reply
+type FirmwareVersion struct {
+	Major int
+	Minor int
+	Patch int
+	Raw   string // the reply exactly as the MAKCU sent it (minus the echo and prompt)
+}
+
+// 🐱 Matches the first version number after "MAKCU", e.g. "km.MAKCU v3.2" or "MAKCU 3.7.1"
+var firmwareVersionRe = regexp.MustCompile(`MAKCU[^0-9\r\n]*?[vV]?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// Parses a km.version() reply into a FirmwareVersion.
+// A reply that identifies a MAKCU but carries no version number parses fine, it is just not Known().
+func ParseFirmwareVersion(reply string) (FirmwareVersion, error) {
+	if !strings.Contains(reply, "MAKCU") {
+		return FirmwareVersion{}, fmt.Errorf("ParseFirmwareVersion: not a MAKCU version reply: %q", reply)
+	}
+
+	// the MAKCU echoes the command back before answering
+	line := reply
+	for _, l := range strings.FieldsFunc(reply, func(r rune) bool { return r == '\r' || r == '\n' }) {
+		if strings.Contains(l, "MAKCU") {
+			line = l
+			break
+		}
+	}
+
+	v := FirmwareVersion{Raw: strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ">>>"))}
+
+	match := firmwareVersionRe.FindStringSubmatch(line)
+	if match == nil {
+		return v, nil
+	}
+
+	for i, dst := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if match[i+1] == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return FirmwareVersion{}, fmt.Errorf("ParseFirmwareVersion: bad version number in %q: %w", reply, err)
+		}
+		*dst = n
+	}
+
+	return v, nil
+}
+
+// 🐱 Reports whether a version number was actually found
+func (v FirmwareVersion) Known() bool {
+	return v.Major != 0 || v.Minor != 0 || v.Patch != 0
+}
+
+// 🐱 Reports whether v is the same as or newer than major.minor.patch
+func (v FirmwareVersion) AtLeast(major, minor, patch int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+
+	return v.Patch >= patch
+}
+
+func (v FirmwareVersion) String() string {
+	if !v.Known() {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// 🐱🐱🐱 Cat firmware version! 🐱🐱🐱
+
+// 🐱 Features that only some firmware versions have
+type Capability uint32
+
+const (
+	CapCurveMove    Capability = 1 << iota // km.move(x, y, segments) and km.move(x, y, segments, cx, cy)
+	CapSideButtons                         // km.side1 / km.side2
+	CapKeyboard                            // keyboard HID output
+	CapLock                                // km.lock_* (blocking the physical mouse)
+	CapCatch                               // km.catch_* (capturing the physical mouse)
+	CapButtonStream                        // km.buttons streaming of the physical buttons
+	CapBinaryFrames                        // 0xDE 0xAD binary frames
+)
+
+var capabilityNames = []struct {
+	cap  Capability
+	name string
+}{
+	{CapCurveMove, "curve move"},
+	{CapSideButtons, "side buttons"},
+	{CapKeyboard, "keyboard"},
+	{CapLock, "lock"},
+	{CapCatch, "catch"},
+	{CapButtonStream, "button stream"},
+	{CapBinaryFrames, "binary frames"},
+}
+
+func (c Capability) String() string {
+	var names []string
+	for _, cn := range capabilityNames {
+		if c&cn.cap != 0 {
+			names = append(names, cn.name)
+			c &^= cn.cap
+		}
+	}
+
+	if c != 0 {
+		names = append(names, fmt.Sprintf("0x%x", uint32(c)))
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "|")
+}
+
+// 🐱 The capability registry (oldest firmware for each capability) and the Km* command methods are generated
+// from commands.json. Add the capability there, then run go generate.
+//go:generate go run ./internal/cmdgen
+
+// 🐱 Returns every capability the given firmware version has
+func CapabilitiesOf(v FirmwareVersion) Capability {
+	var caps Capability
+	for _, c := range capabilityRegistry {
+		if v.AtLeast(c.major, c.minor, c.patch) {
+			caps |= c.cap
+		}
+	}
+
+	return caps
+}
+
+// 🐱🐱🐱 Cat capabilities! 🐱🐱🐱
+
+// Queries km.version() and stores the parsed result on the handle (see Firmware).
+func (m *MakcuHandle) Version() (FirmwareVersion, error) {
+	if m == nil {
+		return FirmwareVersion{}, fmt.Errorf("Version: %w", ErrNotConnected)
+	}
+
+	reply, err := m.probeVersion()
+	if err != nil {
+		return FirmwareVersion{}, fmt.Errorf("Version: %w", err)
+	}
+
+	v, err := ParseFirmwareVersion(reply)
+	if err != nil {
+		return FirmwareVersion{}, fmt.Errorf("Version: %w", err)
+	}
+
+	m.Firmware = v
+	return v, nil
+}
+
+// 🐱 Capabilities of the connected firmware. If the version was never read (or didn't parse) everything is assumed available.
+func (m *MakcuHandle) Capabilities() Capability {
+	if m == nil || !m.Firmware.Known() {
+		return ^Capability(0)
+	}
+
+	return CapabilitiesOf(m.Firmware)
+}
+
+// 🐱 Reports whether the connected firmware has every capability in c
+func (m *MakcuHandle) Supports(c Capability) bool {
+	return m.Capabilities()&c == c
+}
+
+// 🐱 Returns an *UnsupportedError for op if the firmware is missing c
+func (m *MakcuHandle) require(op string, c Capability) error {
+	if m.Supports(c) {
+		return nil
+	}
+
+	return &UnsupportedError{Op: op, Cap: c &^ m.Capabilities(), Firmware: m.Firmware}
+}
+
+// 🐱🐱🐱 Cat firmware! 🐱🐱🐱
@@ -0,0 +1,66 @@
+package makcu
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFirmwareVersion(t *testing.T) {
+	for _, tc := range []struct {
+		reply string
+		want  string
+		err   bool
+	}{
+		{"km.version()\r\nkm.MAKCU v3.2\r\n>>> ", "v3.2.0", false},
+		{"km.MAKCU v3.5.0\r\n", "v3.5.0", false},
+		{"MAKCU 3.7.1", "v3.7.1", false},
+		{"km.version()\r\nkm.MAKCU\r\n>>> ", "unknown", false},
+		{"km.version()\r\n>>> ", "", true},
+	} {
+		v, err := ParseFirmwareVersion(tc.reply)
+		if (err != nil) != tc.err {
+			t.Errorf("ParseFirmwareVersion(%q) error = %v, want error %v", tc.reply, err, tc.err)
+			continue
+		}
+
+		if err == nil && v.String() != tc.want {
+			t.Errorf("ParseFirmwareVersion(%q) = %s, want %s", tc.reply, v, tc.want)
+		}
+	}
+}
+
+func FuzzParseFirmwareVersion(f *testing.F) {
+	cmds, replies := recordedReplies(f)
+	for i, cmd := range cmds {
+		if cmd == "km.version()" {
+			f.Add(replies[i])
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, reply string) {
+		v, err := ParseFirmwareVersion(reply)
+		if err != nil {
+			return
+		}
+
+		if !strings.Contains(reply, "MAKCU") || !strings.Contains(v.Raw, "MAKCU") {
+			t.Fatalf("accepted %q, Raw %q", reply, v.Raw)
+		}
+
+		if v.Major < 0 || v.Minor < 0 || v.Patch < 0 {
+			t.Fatalf("negative version %+v from %q", v, reply)
+		}
+
+		if !v.AtLeast(v.Major, v.Minor, v.Patch) {
+			t.Fatalf("%s is not at least itself", v)
+		}
+
+		// what String prints parses back to the same number
+		if v.Known() {
+			again, err := ParseFirmwareVersion("MAKCU " + v.String())
+			if err != nil || again.Major != v.Major || again.Minor != v.Minor || again.Patch != v.Patch {
+				t.Fatalf("%s reparsed as %+v, %v", v, again, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package makcu
+
+import (
+	"fmt"
+	"time"
+)
+
+// 🐱 What Write does when a write would overflow the MAKCU's receive buffer
+type FlowPolicy uint8
+
+const (
+	FlowOff      FlowPolicy = iota // write anyway, only keep the QueueDelay metric up to date
+	FlowThrottle                   // wait until the link has drained enough for the write to fit
+	FlowReject                     // fail the write with an error matching ErrLinkBusy
+)
+
+func (p FlowPolicy) String() string {
+	switch p {
+	case FlowOff:
+		return "off"
+	case FlowThrottle:
+		return "throttle"
+	case FlowReject:
+		return "reject"
+	}
+
+	return fmt.Sprintf("FlowPolicy(%d)", uint8(p))
+}
+
+// 🐱 Bytes the MAKCU is assumed to be able to hold before it falls behind, unless SetFlowControl is given another size
+const DefaultDeviceBuffer = 1024
+
+// 🐱 Flow control counters
+type FlowStats struct {
+	Throttled uint64        // writes that had to wait for the link to drain
+	Rejected  uint64        // writes refused under FlowReject
+	Waited    time.Duration // total time spent waiting under FlowThrottle
+	MaxDelay  time.Duration // longest queue delay seen right after a write
+}
+
+// Turns on flow control for every write on this handle. The handle keeps an estimate of how many bytes are
+// still on their way to the MAKCU: each write adds to it and it drains at the baud rate (10 bits per byte,
+// 8N1). When a write would push it past bufferSize bytes, policy decides whether Write waits or fails.
+// bufferSize 0 means DefaultDeviceBuffer. Handles made with Open don't know their baud rate and can't use it.
+func (m *MakcuHandle) SetFlowControl(policy FlowPolicy, bufferSize int) error {
+	if m == nil {
+		return fmt.Errorf("SetFlowControl: %w", ErrNotConnected)
+	}
+
+	if policy > FlowReject {
+		return fmt.Errorf("SetFlowControl: unknown policy %v", policy)
+	}
+
+	if bufferSize < 0 {
+		return fmt.Errorf("SetFlowControl: buffer size must not be negative, got %d", bufferSize)
+	}
+
+	if bufferSize == 0 {
+		bufferSize = DefaultDeviceBuffer
+	}
+
+	if policy != FlowOff && m.BaudRate == 0 {
+		return fmt.Errorf("SetFlowControl: baud rate unknown, flow control needs a COM port connection")
+	}
+
+	m.flowMu.Lock()
+	defer m.flowMu.Unlock()
+
+	m.flowPolicy = policy
+	m.flowBuffer = bufferSize
+	return nil
+}
+
+// Returns how long a byte written right now would wait behind the ones already in flight before the MAKCU
+// gets it. 0 when the link is idle or the baud rate is unknown.
+func (m *MakcuHandle) QueueDelay() time.Duration {
+	if m == nil {
+		return 0
+	}
+
+	m.flowMu.Lock()
+	defer m.flowMu.Unlock()
+
+	return max(0, time.Until(m.busyUntil))
+}
+
+// 🐱 Returns a snapshot of the flow control counters
+func (m *MakcuHandle) FlowStats() FlowStats {
+	if m == nil {
+		return FlowStats{}
+	}
+
+	m.flowMu.Lock()
+	defer m.flowMu.Unlock()
+
+	return m.flowStats
+}
+
+// 🐱🐱🐱 Cat flow control! 🐱🐱🐱
+
+// 🐱 Time the link needs to carry n bytes at 8N1
+func wireTime(n int, baudRate uint32) time.Duration {
+	return time.Duration(int64(n) * 10 * int64(time.Second) / int64(baudRate))
+}
+
+// 🐱 Books n bytes onto the link and applies the flow policy. The reservation is made before waiting, so
+// concurrent writers queue up behind each other instead of all waking at once.
+func (m *MakcuHandle) admit(n int) error {
+	baud := m.BaudRate
+	if baud == 0 || n == 0 {
+		return nil
+	}
+
+	m.flowMu.Lock()
+	now := time.Now()
+	busy := m.busyUntil
+	if busy.Before(now) {
+		busy = now
+	}
+
+	// the write fits once at most bufferSize-n bytes are still in flight (or none, if it is bigger than the buffer)
+	var wait time.Duration
+	if m.flowPolicy != FlowOff {
+		fitsAt := busy.Add(-wireTime(max(m.flowBuffer-n, 0), baud))
+		wait = max(0, fitsAt.Sub(now))
+	}
+
+	if wait > 0 && m.flowPolicy == FlowReject {
+		m.flowStats.Rejected++
+		delay := busy.Sub(now)
+		m.flowMu.Unlock()
+		return fmt.Errorf("%w (%d bytes would wait %v behind the queue)", ErrLinkBusy, n, delay)
+	}
+
+	if wait > 0 {
+		m.flowStats.Throttled++
+		m.flowStats.Waited += wait
+	}
+
+	m.busyUntil = busy.Add(wireTime(n, baud))
+	m.flowStats.MaxDelay = max(m.flowStats.MaxDelay, m.busyUntil.Sub(now.Add(wait)))
+	m.flowMu.Unlock()
+
+	if wait > 0 {
+		DebugPrint("Link busy, holding %d bytes for %v", n, wait)
+		time.Sleep(wait)
+	}
+
+	return nil
+}
+
+// 🐱🐱🐱 Cat link! 🐱🐱🐱
@@ -0,0 +1,145 @@
+package makcu
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// 🐱 At 1000 baud a byte takes 10ms, so a 10 byte command is on the wire for 100ms and the margins stay wide
+const (
+	slowBaud   = 1000
+	slowCmd    = "km.left()\r"
+	slowCmdFor = 100 * time.Millisecond
+	slowBuffer = 2 * len(slowCmd) // room for two commands
+)
+
+func openSlow(t *testing.T, policy FlowPolicy) (*MakcuHandle, *countingPort) {
+	t.Helper()
+
+	p := &countingPort{}
+	m := Open(p)
+	m.BaudRate = slowBaud
+	if err := m.SetFlowControl(policy, slowBuffer); err != nil {
+		t.Fatal(err)
+	}
+
+	return m, p
+}
+
+func TestWireTime(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		baud uint32
+		want time.Duration
+	}{
+		{10, 1000, 100 * time.Millisecond},
+		{1, 115200, 86805 * time.Nanosecond},
+		{400000, 4000000, time.Second},
+		{0, 115200, 0},
+	} {
+		if got := wireTime(tc.n, tc.baud); got != tc.want {
+			t.Errorf("wireTime(%d, %d) = %v, want %v", tc.n, tc.baud, got, tc.want)
+		}
+	}
+}
+
+func TestFlowReject(t *testing.T) {
+	m, p := openSlow(t, FlowReject)
+
+	// two fit the buffer, the rest of the burst would overflow it
+	var busy int
+	for i := 0; i < 5; i++ {
+		_, err := m.Write([]byte(slowCmd))
+		if errors.Is(err, ErrLinkBusy) {
+			busy++
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if p.writes != 2 || busy != 3 {
+		t.Errorf("%d writes went out and %d were refused, want 2 and 3", p.writes, busy)
+	}
+
+	stats := m.FlowStats()
+	if stats.Rejected != 3 || stats.Throttled != 0 || stats.Waited != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// both accepted commands are still on the wire
+	if d := m.QueueDelay(); d <= slowCmdFor || d > 2*slowCmdFor {
+		t.Errorf("QueueDelay = %v, want just under %v", d, 2*slowCmdFor)
+	}
+	if stats.MaxDelay <= slowCmdFor || stats.MaxDelay > 2*slowCmdFor {
+		t.Errorf("MaxDelay = %v, want just under %v", stats.MaxDelay, 2*slowCmdFor)
+	}
+}
+
+func TestFlowThrottle(t *testing.T) {
+	m, p := openSlow(t, FlowThrottle)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := m.Write([]byte(slowCmd)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	// the last two each wait for one command to drain
+	stats := m.FlowStats()
+	if p.writes != 4 || stats.Throttled != 2 || stats.Rejected != 0 {
+		t.Errorf("%d writes, stats = %+v, want 4 writes and 2 throttled", p.writes, stats)
+	}
+
+	if stats.Waited < slowCmdFor || stats.Waited > 2*slowCmdFor || elapsed < stats.Waited {
+		t.Errorf("waited %v (%v in all), want between %v and %v", stats.Waited, elapsed, slowCmdFor, 2*slowCmdFor)
+	}
+
+	// the queue never holds more than the buffer
+	if stats.MaxDelay > 2*slowCmdFor {
+		t.Errorf("MaxDelay = %v, over the %v the buffer holds", stats.MaxDelay, 2*slowCmdFor)
+	}
+}
+
+// 🐱 FlowOff never holds anything back but still keeps track of the queue
+func TestFlowOff(t *testing.T) {
+	p := &countingPort{}
+	m := Open(p)
+	m.BaudRate = slowBaud
+
+	for i := 0; i < 5; i++ {
+		if _, err := m.Write([]byte(slowCmd)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if stats := m.FlowStats(); p.writes != 5 || stats.Throttled != 0 || stats.Rejected != 0 {
+		t.Errorf("%d writes, stats = %+v", p.writes, stats)
+	}
+
+	if d := m.QueueDelay(); d <= 4*slowCmdFor || d > 5*slowCmdFor {
+		t.Errorf("QueueDelay = %v, want just under %v", d, 5*slowCmdFor)
+	}
+}
+
+func TestSetFlowControlBadArgs(t *testing.T) {
+	m := Open(&countingPort{})
+	if err := m.SetFlowControl(FlowThrottle, 0); err == nil {
+		t.Error("flow control without a baud rate: no error")
+	}
+
+	// turning it off works whatever the port
+	if err := m.SetFlowControl(FlowOff, 0); err != nil {
+		t.Error(err)
+	}
+
+	m.BaudRate = 115200
+	if err := m.SetFlowControl(FlowReject+1, 0); err == nil {
+		t.Error("unknown policy: no error")
+	}
+	if err := m.SetFlowControl(FlowReject, -1); err == nil {
+		t.Error("negative buffer: no error")
+	}
+}
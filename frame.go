@@ -0,0 +1,130 @@
+package makcu
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 🐱 Binary frames look like 0xDE 0xAD <length u16 LE> <cmd> <payload>, where length counts cmd + payload
+const (
+	frameMagic0  = 0xDE
+	frameMagic1  = 0xAD
+	frameHeader  = 4 // magic + length
+	frameSetBaud = 0xA5
+	frameMotion  = 0xB0 // MAKCU -> host while km.catch_xy is on: dx int16, dy int16, wheel int8
+
+	motionSize = 1 + 5 // length field of a motion frame: cmd + payload
+)
+
+var (
+	errFrameShort = errors.New("incomplete binary frame")
+	errFrameMagic = errors.New("not a binary frame")
+)
+
+// 🐱 Appends a binary frame carrying cmd and payload
+func appendFrame(dst []byte, cmd byte, payload []byte) []byte {
+	dst = append(dst, frameMagic0, frameMagic1)
+	dst = binary.LittleEndian.AppendUint16(dst, uint16(1+len(payload)))
+	dst = append(dst, cmd)
+	return append(dst, payload...)
+}
+
+// 🐱 Decodes the binary frame at the start of b. n is how many bytes it took up. payload points into b.
+// Returns errFrameShort when b holds only part of a frame and errFrameMagic when it isn't one at all.
+func parseFrame(b []byte) (cmd byte, payload []byte, n int, err error) {
+	if len(b) >= 1 && b[0] != frameMagic0 || len(b) >= 2 && b[1] != frameMagic1 {
+		return 0, nil, 0, errFrameMagic
+	}
+
+	if len(b) < frameHeader {
+		return 0, nil, 0, errFrameShort
+	}
+
+	size := int(binary.LittleEndian.Uint16(b[2:frameHeader]))
+	if size == 0 {
+		return 0, nil, 0, fmt.Errorf("%w: zero length", errFrameMagic)
+	}
+
+	if len(b) < frameHeader+size {
+		return 0, nil, 0, errFrameShort
+	}
+
+	return b[frameHeader], b[frameHeader+1 : frameHeader+size], frameHeader + size, nil
+}
+
+// 🐱 Payload of the set baud rate frame
+func setBaudFrame(baudRate uint32) []byte {
+	return appendFrame(nil, frameSetBaud, binary.LittleEndian.AppendUint32(nil, baudRate))
+}
+
+// 🐱 Reports whether b could be the start of a frame the MAKCU sends. Motion frames are the only ones, so a
+// different length or cmd means the 0xDE was noise (or a frame from something else) and the reader shouldn't
+// wait for up to 64KB of "payload" that is really the replies after it.
+func inboundFrame(b []byte) bool {
+	switch {
+	case len(b) >= 2 && b[1] != frameMagic1:
+		return false
+	case len(b) >= frameHeader && binary.LittleEndian.Uint16(b[2:frameHeader]) != motionSize:
+		return false
+	case len(b) > frameHeader && b[frameHeader] != frameMotion:
+		return false
+	}
+
+	return len(b) >= 1 && b[0] == frameMagic0
+}
+
+// 🐱 Decodes the payload of a motion frame
+func parseMotion(payload []byte) (dx, dy, wheel int, ok bool) {
+	if len(payload) != motionSize-1 {
+		return 0, 0, 0, false
+	}
+
+	dx = int(int16(binary.LittleEndian.Uint16(payload[0:2])))
+	dy = int(int16(binary.LittleEndian.Uint16(payload[2:4])))
+	return dx, dy, int(int8(payload[4])), true
+}
+
+// 🐱🐱🐱 Cat binary frames! 🐱🐱🐱
+
+// 🐱 Parses "km.<fn>(arg, arg, ...)" (trailing \r optional) back into what appendCall was given.
+// Arguments must be integers, which is all the library ever sends.
+func parseCall(cmd string) (fn string, args []int, err error) {
+	s := strings.TrimSpace(cmd)
+
+	rest, ok := strings.CutPrefix(s, "km.")
+	if !ok {
+		return "", nil, fmt.Errorf("parseCall: %q does not start with km.", cmd)
+	}
+
+	open := strings.IndexByte(rest, '(')
+	if open <= 0 || !strings.HasSuffix(rest, ")") {
+		return "", nil, fmt.Errorf("parseCall: %q is not a call", cmd)
+	}
+
+	fn = rest[:open]
+	for _, r := range fn {
+		if r != '_' && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return "", nil, fmt.Errorf("parseCall: bad function name %q", fn)
+		}
+	}
+
+	inner := strings.TrimSpace(rest[open+1 : len(rest)-1])
+	if inner == "" {
+		return fn, nil, nil
+	}
+
+	for _, a := range strings.Split(inner, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(a))
+		if err != nil {
+			return "", nil, fmt.Errorf("parseCall: bad argument in %q: %w", cmd, err)
+		}
+		args = append(args, v)
+	}
+
+	return fn, args, nil
+}
+
+// 🐱🐱🐱 Cat command parser! 🐱🐱🐱
@@ -0,0 +1,112 @@
+package makcu
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// 🐱 Function names appendCall is ever given: lowercase letters, digits and underscores
+func validFn(fn string) bool {
+	if fn == "" {
+		return false
+	}
+
+	for _, r := range fn {
+		if r != '_' && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func FuzzParseCall(f *testing.F) {
+	for _, line := range recordedCommands(f) {
+		f.Add(line, uint8(0), 0, 0, 0)
+	}
+
+	f.Fuzz(func(t *testing.T, fn string, n uint8, a, b, c int) {
+		// anything at all must parse or fail without panicking, and what parses must encode back to itself
+		if gotFn, gotArgs, err := parseCall(fn); err == nil {
+			again := string(appendCall(nil, gotFn, gotArgs...))
+			fn2, args2, err := parseCall(again)
+			if err != nil || fn2 != gotFn || !reflect.DeepEqual(args2, gotArgs) {
+				t.Fatalf("%q parsed to %s%v, which encodes to %q and parses to %s%v, %v", fn, gotFn, gotArgs, again, fn2, args2, err)
+			}
+		}
+
+		if !validFn(fn) {
+			return
+		}
+
+		args := []int{a, b, c}[:n%4]
+		cmd := appendCall(nil, fn, args...)
+		gotFn, gotArgs, err := parseCall(string(cmd))
+		if err != nil {
+			t.Fatalf("appendCall(%q, %v) = %q, which doesn't parse: %v", fn, args, cmd, err)
+		}
+
+		if len(args) == 0 {
+			args = nil
+		}
+		if gotFn != fn || !reflect.DeepEqual(gotArgs, args) {
+			t.Fatalf("appendCall(%q, %v) = %q parsed back as %q %v", fn, args, cmd, gotFn, gotArgs)
+		}
+	})
+}
+
+func TestParseFrame(t *testing.T) {
+	baud := setBaudFrame(4000000)
+	if want := []byte{0xDE, 0xAD, 5, 0, 0xA5, 0x00, 0x09, 0x3D, 0x00}; !bytes.Equal(baud, want) {
+		t.Fatalf("setBaudFrame(4000000) = % X, want % X", baud, want)
+	}
+
+	cmd, payload, n, err := parseFrame(append(baud, "km.left()"...))
+	if err != nil || cmd != frameSetBaud || n != len(baud) || !bytes.Equal(payload, baud[5:]) {
+		t.Errorf("parseFrame = 0x%02X % X %d %v", cmd, payload, n, err)
+	}
+
+	for i := 0; i < len(baud); i++ {
+		if _, _, _, err := parseFrame(baud[:i]); err != errFrameShort {
+			t.Errorf("first %d bytes: %v, want errFrameShort", i, err)
+		}
+	}
+
+	if _, _, _, err := parseFrame([]byte("km.move(1, 2)\r")); err == nil {
+		t.Error("text parsed as a frame")
+	}
+}
+
+func FuzzParseFrame(f *testing.F) {
+	for _, rec := range recordedTraffic(f) {
+		for _, chunk := range append(rec.rx, rec.tx...) {
+			for i, c := range chunk {
+				if c == frameMagic0 {
+					f.Add(chunk[i:])
+				}
+			}
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		cmd, payload, n, err := parseFrame(b)
+		if err != nil {
+			return
+		}
+
+		if n < frameHeader+1 || n > len(b) {
+			t.Fatalf("frame of %d bytes in %d bytes of input", n, len(b))
+		}
+
+		if again := appendFrame(nil, cmd, payload); !bytes.Equal(again, b[:n]) {
+			t.Fatalf("re-encoded as % X, was % X", again, b[:n])
+		}
+
+		if cmd == frameMotion {
+			if dx, dy, wheel, ok := parseMotion(payload); ok && (dx < -32768 || dx > 32767 || dy < -32768 || dy > 32767 || wheel < -128 || wheel > 127) {
+				t.Fatalf("motion out of range: %d %d %d", dx, dy, wheel)
+			}
+		}
+	})
+}
@@ -0,0 +1,364 @@
+// Command cmdgen turns commands.json, the machine readable description of the MAKCU's km.* commands, into:
+//
+//   - commands_gen.go: one typed, validated, capability gated Km* method per command (queries return the
+//     MAKCU's answer), plus the capability registry and the list of idempotent commands
+//   - makcufake/commands_gen.go: the signatures the fake device checks incoming commands against
+//   - cmd/makcu/commands_gen.go: one makcu CLI subcommand per command
+//
+// It is run by `go generate` from the repository root. A command's minimum firmware version is the "since" of
+// the capability it names. Commands without a capability work on every firmware.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+type spec struct {
+	Capabilities []capability `json:"capabilities"`
+	Commands     []command    `json:"commands"`
+
+	Idempotent []string `json:"-"` // firmware functions with an idempotent form, filled in by check
+}
+
+type capability struct {
+	Name  string `json:"name"`
+	Since string `json:"since"`
+
+	Major, Minor, Patch int `json:"-"`
+}
+
+type command struct {
+	Name       string `json:"name"` // Go name, the method is Km<Name>
+	Fn         string `json:"fn"`   // firmware function, km.<fn>(...)
+	Doc        string `json:"doc"`
+	Args       []arg  `json:"args"`
+	Cap        string `json:"cap"`
+	Idempotent bool   `json:"idempotent"`
+	Query      bool   `json:"query"` // the MAKCU answers with an integer, which the method returns
+
+	Since string `json:"-"` // filled in from Cap
+}
+
+type arg struct {
+	Name string `json:"name"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+}
+
+var (
+	goName   = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	fwName   = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	argName  = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+	versionR = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)$`)
+)
+
+func main() {
+	specPath := flag.String("spec", "commands.json", "command spec to generate from")
+	root := flag.String("root", ".", "repository root the files are written under")
+	flag.Parse()
+
+	if err := run(*specPath, *root); err != nil {
+		fmt.Fprintln(os.Stderr, "cmdgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, root string) error {
+	raw, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+
+	var s spec
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return fmt.Errorf("%s: %w", specPath, err)
+	}
+
+	if err := check(&s); err != nil {
+		return fmt.Errorf("%s: %w", specPath, err)
+	}
+
+	outputs := []struct {
+		path string
+		tmpl *template.Template
+	}{
+		{"commands_gen.go", pkgTmpl},
+		{filepath.Join("makcufake", "commands_gen.go"), fakeTmpl},
+		{filepath.Join("cmd", "makcu", "commands_gen.go"), cliTmpl},
+	}
+
+	for _, out := range outputs {
+		var buf bytes.Buffer
+		if err := out.tmpl.Execute(&buf, s); err != nil {
+			return fmt.Errorf("%s: %w", out.path, err)
+		}
+
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("%s: generated code doesn't parse: %w\n%s", out.path, err, buf.Bytes())
+		}
+
+		if err := os.WriteFile(filepath.Join(root, out.path), src, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// check validates the spec and fills in the derived fields
+func check(s *spec) error {
+	caps := map[string]capability{}
+	for i := range s.Capabilities {
+		c := &s.Capabilities[i]
+		if !goName.MatchString(c.Name) || !strings.HasPrefix(c.Name, "Cap") {
+			return fmt.Errorf("capability %q: name must look like CapSomething", c.Name)
+		}
+
+		m := versionR.FindStringSubmatch(c.Since)
+		if m == nil {
+			return fmt.Errorf("capability %s: since %q is not major.minor.patch", c.Name, c.Since)
+		}
+		c.Major, _ = strconv.Atoi(m[1])
+		c.Minor, _ = strconv.Atoi(m[2])
+		c.Patch, _ = strconv.Atoi(m[3])
+
+		if _, dup := caps[c.Name]; dup {
+			return fmt.Errorf("capability %s listed twice", c.Name)
+		}
+		caps[c.Name] = *c
+	}
+
+	names := map[string]bool{}
+	arity := map[string]map[int]bool{}
+	for i := range s.Commands {
+		c := &s.Commands[i]
+		if !goName.MatchString(c.Name) {
+			return fmt.Errorf("command %q: name must be an exported Go identifier", c.Name)
+		}
+
+		if names[c.Name] {
+			return fmt.Errorf("command %s listed twice", c.Name)
+		}
+		names[c.Name] = true
+
+		if !fwName.MatchString(c.Fn) {
+			return fmt.Errorf("command %s: bad firmware function %q", c.Name, c.Fn)
+		}
+
+		// the fake tells overloads of one function apart by their argument count
+		if arity[c.Fn] == nil {
+			arity[c.Fn] = map[int]bool{}
+		}
+		if arity[c.Fn][len(c.Args)] {
+			return fmt.Errorf("command %s: km.%s already has a form with %d arguments", c.Name, c.Fn, len(c.Args))
+		}
+		arity[c.Fn][len(c.Args)] = true
+
+		if c.Query && (len(c.Args) > 0 || c.Idempotent) {
+			return fmt.Errorf("command %s: queries take no arguments and aren't retried", c.Name)
+		}
+
+		for _, a := range c.Args {
+			if !argName.MatchString(a.Name) {
+				return fmt.Errorf("command %s: bad argument name %q", c.Name, a.Name)
+			}
+
+			if a.Min > a.Max {
+				return fmt.Errorf("command %s: argument %s has min %d > max %d", c.Name, a.Name, a.Min, a.Max)
+			}
+		}
+
+		if c.Cap != "" {
+			cp, ok := caps[c.Cap]
+			if !ok {
+				return fmt.Errorf("command %s: unknown capability %q", c.Name, c.Cap)
+			}
+			c.Since = cp.Since
+		}
+
+		if c.Idempotent && !slices.Contains(s.Idempotent, c.Fn) {
+			s.Idempotent = append(s.Idempotent, c.Fn)
+		}
+	}
+
+	return nil
+}
+
+var funcs = template.FuncMap{
+	// "MoveBezier" -> "move-bezier"
+	"kebab": func(s string) string {
+		var b strings.Builder
+		for i, r := range s {
+			if unicode.IsUpper(r) {
+				if i > 0 {
+					b.WriteByte('-')
+				}
+				r = unicode.ToLower(r)
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	},
+	"params": func(args []arg) string {
+		var p []string
+		for _, a := range args {
+			p = append(p, a.Name)
+		}
+		if len(p) == 0 {
+			return ""
+		}
+		return strings.Join(p, ", ") + " int"
+	},
+	"names": func(args []arg) string {
+		var p []string
+		for _, a := range args {
+			p = append(p, a.Name)
+		}
+		return strings.Join(p, ", ")
+	},
+	"indexed": func(args []arg) string {
+		var p []string
+		for i := range args {
+			p = append(p, fmt.Sprintf("a[%d]", i))
+		}
+		return strings.Join(p, ", ")
+	},
+	"quoted": func(args []arg) string {
+		var p []string
+		for _, a := range args {
+			p = append(p, strconv.Quote(a.Name))
+		}
+		return strings.Join(p, ", ")
+	},
+}
+
+const header = `// Code generated by cmdgen from commands.json. DO NOT EDIT.
+
+`
+
+var pkgTmpl = template.Must(template.New("pkg").Funcs(funcs).Parse(header + `package makcu
+
+import "fmt"
+
+// 🐱 Oldest firmware each capability is available on
+var capabilityRegistry = []struct {
+	cap                 Capability
+	major, minor, patch int
+}{
+{{- range .Capabilities}}
+	{ {{- .Name}}, {{.Major}}, {{.Minor}}, {{.Patch -}} },
+{{- end}}
+}
+
+// 🐱 Firmware functions that set an absolute state, so sending them twice does no harm
+var idempotentCommands = map[string]bool{
+{{- range .Idempotent}}
+	{{printf "%q" .}}: true,
+{{- end}}
+}
+
+{{range .Commands}}
+{{- if .Query}}
+// Km{{.Name}} sends km.{{.Fn}}() and returns what the MAKCU answers. {{.Doc}}
+{{- if .Cap}}
+// Needs firmware {{.Since}} or newer ({{.Cap}}).
+{{- end}}
+func (m *MakcuHandle) Km{{.Name}}() (int, error) {
+	if m == nil {
+		return 0, fmt.Errorf("Km{{.Name}}: %w", ErrNotConnected)
+	}
+{{if .Cap}}
+	if err := m.require("Km{{.Name}}", {{.Cap}}); err != nil {
+		return 0, err
+	}
+{{end}}
+	return m.query("Km{{.Name}}", {{printf "%q" .Fn}})
+}
+{{else}}
+// Km{{.Name}} sends km.{{.Fn}}({{names .Args}}). {{.Doc}}
+{{- if .Cap}}
+// Needs firmware {{.Since}} or newer ({{.Cap}}).
+{{- end}}
+func (m *MakcuHandle) Km{{.Name}}({{params .Args}}) error {
+	if m == nil {
+		return fmt.Errorf("Km{{.Name}}: %w", ErrNotConnected)
+	}
+{{if .Args}}
+	if err := validateKm{{.Name}}({{names .Args}}); err != nil {
+		return err
+	}
+{{end}}
+{{- if .Cap}}
+	if err := m.require("Km{{.Name}}", {{.Cap}}); err != nil {
+		return err
+	}
+{{end}}
+	return m.call({{printf "%q" .Fn}}{{if .Args}}, {{names .Args}}{{end}})
+}
+{{if .Args}}
+func validateKm{{.Name}}({{params .Args}}) error {
+{{- $name := .Name}}
+{{- range .Args}}
+	if err := checkRange("Km{{$name}}", {{printf "%q" .Name}}, {{.Name}}, {{.Min}}, {{.Max}}); err != nil {
+		return err
+	}
+{{- end}}
+
+	return nil
+}
+{{end}}
+{{- end}}
+{{end}}
+`))
+
+var fakeTmpl = template.Must(template.New("fake").Funcs(funcs).Parse(header + `package makcufake
+
+// signatures lists every km.* form the firmware accepts
+var signatures = []signature{
+{{- range .Commands}}
+	{fn: {{printf "%q" .Fn}}, name: {{printf "%q" .Name}}, {{if .Query}}query: true, {{end}}args: []arg{
+	{{- range .Args}}{ {{- printf "%q" .Name}}, {{.Min}}, {{.Max -}} },{{end -}}
+	}},
+{{- end}}
+}
+`))
+
+var cliTmpl = template.Must(template.New("cli").Funcs(funcs).Parse(header + `package main
+
+import makcu "github.com/nullpkt/Makcu-Go"
+
+// generated has one subcommand per command in commands.json
+var generated = []subcommand{
+{{- range .Commands}}
+	{
+		name: {{printf "%q" (kebab .Name)}},
+		args: []string{ {{- quoted .Args -}} },
+		doc:  {{printf "%q" .Doc}},
+{{- if .Query}}
+		query: func(m *makcu.MakcuHandle) (int, error) {
+			return m.Km{{.Name}}()
+		},
+{{- else}}
+		run: func(m *makcu.MakcuHandle, a []int) error {
+			return m.Km{{.Name}}({{indexed .Args}})
+		},
+{{- end}}
+	},
+{{- end}}
+}
+`))
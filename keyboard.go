@@ -0,0 +1,220 @@
+package makcu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (k Key) String() string {
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Key(0x%02X)", uint8(k))
+}
+
+// 🐱 Other names ParseKey understands
+var keyAliases = map[string]Key{
+	"esc":    KeyEscape,
+	"return": KeyEnter,
+	"ctrl":   KeyLeftCtrl,
+	"shift":  KeyLeftShift,
+	"alt":    KeyLeftAlt,
+	"altgr":  KeyRightAlt,
+	"win":    KeyLeftGUI,
+	"cmd":    KeyLeftGUI,
+	"del":    KeyDelete,
+	"pgup":   KeyPageUp,
+	"pgdn":   KeyPageDown,
+	"-":      KeyMinus,
+	"=":      KeyEqual,
+	"[":      KeyLeftBracket,
+	"]":      KeyRightBracket,
+	"\\":     KeyBackslash,
+	";":      KeySemicolon,
+	"'":      KeyApostrophe,
+	"`":      KeyGrave,
+	",":      KeyComma,
+	".":      KeyPeriod,
+	"/":      KeySlash,
+}
+
+// 🐱 Parses a key name as printed by String ("a", "f5", "leftctrl", ...) or a common alias ("esc", "ctrl", ";").
+// Case doesn't matter.
+func ParseKey(s string) (Key, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if k, ok := keyAliases[name]; ok {
+		return k, nil
+	}
+
+	for k, n := range keyNames {
+		if n == name {
+			return k, nil
+		}
+	}
+
+	return 0, fmt.Errorf("ParseKey: unknown key %q", s)
+}
+
+// 🐱 Reports whether k is one of the eight modifier keys
+func (k Key) IsModifier() bool {
+	return k >= KeyLeftCtrl && k <= KeyRightGUI
+}
+
+// 🐱 A set of modifier keys, laid out like the modifier byte of a HID keyboard report
+type Modifier uint8
+
+const (
+	ModLeftCtrl Modifier = 1 << iota
+	ModLeftShift
+	ModLeftAlt
+	ModLeftGUI
+	ModRightCtrl
+	ModRightShift
+	ModRightAlt // AltGr on most non-US layouts
+	ModRightGUI
+
+	ModCtrl  = ModLeftCtrl
+	ModShift = ModLeftShift
+	ModAlt   = ModLeftAlt
+	ModGUI   = ModLeftGUI
+	ModAltGr = ModRightAlt
+)
+
+// 🐱 Returns the modifier keys in the set, left ones first
+func (mod Modifier) Keys() []Key {
+	var keys []Key
+	for i := 0; i < 8; i++ {
+		if mod&(1<<i) != 0 {
+			keys = append(keys, KeyLeftCtrl+Key(i))
+		}
+	}
+
+	return keys
+}
+
+func (mod Modifier) String() string {
+	var names []string
+	for _, k := range mod.Keys() {
+		names = append(names, k.String())
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "+")
+}
+
+// 🐱 Parses a shortcut like "ctrl+shift+t" into its modifiers and the key. A lone modifier is returned as the key.
+func ParseShortcut(s string) (Modifier, Key, error) {
+	parts := strings.Split(s, "+")
+	var mod Modifier
+	for i, p := range parts {
+		k, err := ParseKey(p)
+		if err != nil {
+			return 0, 0, fmt.Errorf("ParseShortcut: %q: %w", s, err)
+		}
+
+		if i == len(parts)-1 {
+			return mod, k, nil
+		}
+
+		if !k.IsModifier() {
+			return 0, 0, fmt.Errorf("ParseShortcut: %q: %s is not a modifier", s, k)
+		}
+		mod |= 1 << (k - KeyLeftCtrl)
+	}
+
+	return 0, 0, fmt.Errorf("ParseShortcut: empty shortcut")
+}
+
+// 🐱🐱🐱 Cat key names! 🐱🐱🐱
+
+// 🐱 Holds a key down. Needs firmware with keyboard support (CapKeyboard).
+func (m *MakcuHandle) KeyDown(k Key) error {
+	if m == nil {
+		return fmt.Errorf("KeyDown: %w", ErrNotConnected)
+	}
+
+	err := m.KmKeyDown(int(k))
+	if err != nil {
+		DebugPrint("Failed to press key %s: %v", k, err)
+		return err
+	}
+
+	return nil
+}
+
+// 🐱 Releases a key
+func (m *MakcuHandle) KeyUp(k Key) error {
+	if m == nil {
+		return fmt.Errorf("KeyUp: %w", ErrNotConnected)
+	}
+
+	err := m.KmKeyUp(int(k))
+	if err != nil {
+		DebugPrint("Failed to release key %s: %v", k, err)
+		return err
+	}
+
+	return nil
+}
+
+// Presses and releases a key, holding it down for hold. With hold 0 both go out in a single write.
+func (m *MakcuHandle) KeyPress(k Key, hold time.Duration) error {
+	return m.KeyCombo(0, k, hold)
+}
+
+// Presses a key with modifiers held: the modifiers go down, then the key, and after hold everything is released
+// in reverse order. With hold 0 the whole sequence goes out in a single write.
+//
+//	err := MakcuConn.KeyCombo(makcu.ModCtrl|makcu.ModShift, makcu.KeyT, 0)
+func (m *MakcuHandle) KeyCombo(mod Modifier, k Key, hold time.Duration) error {
+	if m == nil {
+		return fmt.Errorf("KeyCombo: %w", ErrNotConnected)
+	}
+
+	if err := m.require("KeyCombo", CapKeyboard); err != nil {
+		return err
+	}
+
+	mods := mod.Keys()
+
+	m.mu.Lock()
+	m.cmdBuf = m.cmdBuf[:0]
+	for _, mk := range mods {
+		m.cmdBuf = appendCall(m.cmdBuf, "down", int(mk))
+	}
+	m.cmdBuf = appendCall(m.cmdBuf, "down", int(k))
+	if hold <= 0 {
+		m.cmdBuf = appendKeyUps(m.cmdBuf, mods, k)
+	}
+	err := m.execLocked(m.cmdBuf)
+	m.mu.Unlock()
+
+	if err != nil || hold <= 0 {
+		return err
+	}
+
+	time.Sleep(hold)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cmdBuf = appendKeyUps(m.cmdBuf[:0], mods, k)
+	return m.execLocked(m.cmdBuf)
+}
+
+// 🐱 Appends the releases for a key and its modifiers, in the reverse order they were pressed
+func appendKeyUps(dst []byte, mods []Key, k Key) []byte {
+	dst = appendCall(dst, "up", int(k))
+	for i := len(mods) - 1; i >= 0; i-- {
+		dst = appendCall(dst, "up", int(mods[i]))
+	}
+
+	return dst
+}
+
+// 🐱🐱🐱 Cat keyboard! 🐱🐱🐱
@@ -0,0 +1,79 @@
+package makcu
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeyCombo(t *testing.T) {
+	ctrl, shift, key := int(KeyLeftCtrl), int(KeyLeftShift), int(KeyT)
+	for _, tc := range []struct {
+		name string
+		fn   func(m *MakcuHandle) error
+		want []string
+	}{
+		{"KeyDown", func(m *MakcuHandle) error { return m.KeyDown(KeyT) }, []string{
+			fmt.Sprintf("KeyDown(%d)", key),
+		}},
+		{"KeyUp", func(m *MakcuHandle) error { return m.KeyUp(KeyT) }, []string{
+			fmt.Sprintf("KeyUp(%d)", key),
+		}},
+		{"KeyPress", func(m *MakcuHandle) error { return m.KeyPress(KeyT, 0) }, []string{
+			fmt.Sprintf("KeyDown(%d)", key), fmt.Sprintf("KeyUp(%d)", key),
+		}},
+		{"KeyCombo", func(m *MakcuHandle) error { return m.KeyCombo(ModCtrl|ModShift, KeyT, 0) }, []string{
+			fmt.Sprintf("KeyDown(%d)", ctrl), fmt.Sprintf("KeyDown(%d)", shift), fmt.Sprintf("KeyDown(%d)", key),
+			fmt.Sprintf("KeyUp(%d)", key), fmt.Sprintf("KeyUp(%d)", shift), fmt.Sprintf("KeyUp(%d)", ctrl),
+		}},
+		{"KeyCombo held", func(m *MakcuHandle) error { return m.KeyCombo(ModCtrl|ModShift, KeyT, time.Millisecond) }, []string{
+			fmt.Sprintf("KeyDown(%d)", ctrl), fmt.Sprintf("KeyDown(%d)", shift), fmt.Sprintf("KeyDown(%d)", key),
+			fmt.Sprintf("KeyUp(%d)", key), fmt.Sprintf("KeyUp(%d)", shift), fmt.Sprintf("KeyUp(%d)", ctrl),
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, dev := openFake(t, "v3.5.0")
+			if err := tc.fn(m); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := callNames(dev); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("calls = %v, want %v", got, tc.want)
+			}
+
+			old, oldDev := openFake(t, "v3.4.0")
+			err := tc.fn(old)
+			var ue *UnsupportedError
+			if !errors.Is(err, ErrUnsupported) || !errors.As(err, &ue) || ue.Cap != CapKeyboard {
+				t.Errorf("on v3.4.0: got %v, want ErrUnsupported for the keyboard", err)
+			}
+
+			if got := oldDev.Calls(); len(got) != 0 {
+				t.Errorf("on v3.4.0: sent %v anyway", got)
+			}
+		})
+	}
+}
+
+// 🐱 With hold 0 the downs and ups share one write, so nothing can land between them. A hold splits it in two.
+func TestKeyComboWrites(t *testing.T) {
+	for _, tc := range []struct {
+		hold   time.Duration
+		writes int
+	}{
+		{0, 1},
+		{time.Millisecond, 2},
+	} {
+		p := &countingPort{}
+		m := Open(p)
+		if err := m.KeyCombo(ModCtrl|ModAlt, KeyDelete, tc.hold); err != nil {
+			t.Fatal(err)
+		}
+
+		if p.writes != tc.writes {
+			t.Errorf("hold %v: %d writes, want %d", tc.hold, p.writes, tc.writes)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package makcu
+
+// 🐱 A keyboard key, as its HID usage code (usage page 0x07). Only the names below are listed, but any code
+// from 0 to 255 can be sent.
+type Key uint8
+
+// 🐱 Key names follow the US layout: KeyA is the key labeled A there, whatever the host's layout makes of it.
+const (
+	KeyA              Key = 0x04
+	KeyB              Key = 0x05
+	KeyC              Key = 0x06
+	KeyD              Key = 0x07
+	KeyE              Key = 0x08
+	KeyF              Key = 0x09
+	KeyG              Key = 0x0A
+	KeyH              Key = 0x0B
+	KeyI              Key = 0x0C
+	KeyJ              Key = 0x0D
+	KeyK              Key = 0x0E
+	KeyL              Key = 0x0F
+	KeyM              Key = 0x10
+	KeyN              Key = 0x11
+	KeyO              Key = 0x12
+	KeyP              Key = 0x13
+	KeyQ              Key = 0x14
+	KeyR              Key = 0x15
+	KeyS              Key = 0x16
+	KeyT              Key = 0x17
+	KeyU              Key = 0x18
+	KeyV              Key = 0x19
+	KeyW              Key = 0x1A
+	KeyX              Key = 0x1B
+	KeyY              Key = 0x1C
+	KeyZ              Key = 0x1D
+	Key1              Key = 0x1E
+	Key2              Key = 0x1F
+	Key3              Key = 0x20
+	Key4              Key = 0x21
+	Key5              Key = 0x22
+	Key6              Key = 0x23
+	Key7              Key = 0x24
+	Key8              Key = 0x25
+	Key9              Key = 0x26
+	Key0              Key = 0x27
+	KeyEnter          Key = 0x28
+	KeyEscape         Key = 0x29
+	KeyBackspace      Key = 0x2A
+	KeyTab            Key = 0x2B
+	KeySpace          Key = 0x2C
+	KeyMinus          Key = 0x2D
+	KeyEqual          Key = 0x2E
+	KeyLeftBracket    Key = 0x2F
+	KeyRightBracket   Key = 0x30
+	KeyBackslash      Key = 0x31
+	KeyNonUSHash      Key = 0x32 // # and ~ next to Enter on ISO keyboards
+	KeySemicolon      Key = 0x33
+	KeyApostrophe     Key = 0x34
+	KeyGrave          Key = 0x35 // ` and ~ left of 1
+	KeyComma          Key = 0x36
+	KeyPeriod         Key = 0x37
+	KeySlash          Key = 0x38
+	KeyCapsLock       Key = 0x39
+	KeyF1             Key = 0x3A
+	KeyF2             Key = 0x3B
+	KeyF3             Key = 0x3C
+	KeyF4             Key = 0x3D
+	KeyF5             Key = 0x3E
+	KeyF6             Key = 0x3F
+	KeyF7             Key = 0x40
+	KeyF8             Key = 0x41
+	KeyF9             Key = 0x42
+	KeyF10            Key = 0x43
+	KeyF11            Key = 0x44
+	KeyF12            Key = 0x45
+	KeyPrintScreen    Key = 0x46
+	KeyScrollLock     Key = 0x47
+	KeyPause          Key = 0x48
+	KeyInsert         Key = 0x49
+	KeyHome           Key = 0x4A
+	KeyPageUp         Key = 0x4B
+	KeyDelete         Key = 0x4C
+	KeyEnd            Key = 0x4D
+	KeyPageDown       Key = 0x4E
+	KeyRight          Key = 0x4F
+	KeyLeft           Key = 0x50
+	KeyDown           Key = 0x51
+	KeyUp             Key = 0x52
+	KeyNumLock        Key = 0x53
+	KeyPadSlash       Key = 0x54
+	KeyPadAsterisk    Key = 0x55
+	KeyPadMinus       Key = 0x56
+	KeyPadPlus        Key = 0x57
+	KeyPadEnter       Key = 0x58
+	KeyPad1           Key = 0x59
+	KeyPad2           Key = 0x5A
+	KeyPad3           Key = 0x5B
+	KeyPad4           Key = 0x5C
+	KeyPad5           Key = 0x5D
+	KeyPad6           Key = 0x5E
+	KeyPad7           Key = 0x5F
+	KeyPad8           Key = 0x60
+	KeyPad9           Key = 0x61
+	KeyPad0           Key = 0x62
+	KeyPadPeriod      Key = 0x63
+	KeyNonUSBackslash Key = 0x64 // \ and | next to left Shift on ISO keyboards
+	KeyApplication    Key = 0x65
+	KeyLeftCtrl       Key = 0xE0
+	KeyLeftShift      Key = 0xE1
+	KeyLeftAlt        Key = 0xE2
+	KeyLeftGUI        Key = 0xE3 // Windows / Command
+	KeyRightCtrl      Key = 0xE4
+	KeyRightShift     Key = 0xE5
+	KeyRightAlt       Key = 0xE6
+	KeyRightGUI       Key = 0xE7
+)
+
+// 🐱 Lower case names for String and ParseKey
+var keyNames = map[Key]string{
+	KeyA:              "a",
+	KeyB:              "b",
+	KeyC:              "c",
+	KeyD:              "d",
+	KeyE:              "e",
+	KeyF:              "f",
+	KeyG:              "g",
+	KeyH:              "h",
+	KeyI:              "i",
+	KeyJ:              "j",
+	KeyK:              "k",
+	KeyL:              "l",
+	KeyM:              "m",
+	KeyN:              "n",
+	KeyO:              "o",
+	KeyP:              "p",
+	KeyQ:              "q",
+	KeyR:              "r",
+	KeyS:              "s",
+	KeyT:              "t",
+	KeyU:              "u",
+	KeyV:              "v",
+	KeyW:              "w",
+	KeyX:              "x",
+	KeyY:              "y",
+	KeyZ:              "z",
+	Key1:              "1",
+	Key2:              "2",
+	Key3:              "3",
+	Key4:              "4",
+	Key5:              "5",
+	Key6:              "6",
+	Key7:              "7",
+	Key8:              "8",
+	Key9:              "9",
+	Key0:              "0",
+	KeyEnter:          "enter",
+	KeyEscape:         "escape",
+	KeyBackspace:      "backspace",
+	KeyTab:            "tab",
+	KeySpace:          "space",
+	KeyMinus:          "minus",
+	KeyEqual:          "equal",
+	KeyLeftBracket:    "leftbracket",
+	KeyRightBracket:   "rightbracket",
+	KeyBackslash:      "backslash",
+	KeyNonUSHash:      "nonushash",
+	KeySemicolon:      "semicolon",
+	KeyApostrophe:     "apostrophe",
+	KeyGrave:          "grave",
+	KeyComma:          "comma",
+	KeyPeriod:         "period",
+	KeySlash:          "slash",
+	KeyCapsLock:       "capslock",
+	KeyF1:             "f1",
+	KeyF2:             "f2",
+	KeyF3:             "f3",
+	KeyF4:             "f4",
+	KeyF5:             "f5",
+	KeyF6:             "f6",
+	KeyF7:             "f7",
+	KeyF8:             "f8",
+	KeyF9:             "f9",
+	KeyF10:            "f10",
+	KeyF11:            "f11",
+	KeyF12:            "f12",
+	KeyPrintScreen:    "printscreen",
+	KeyScrollLock:     "scrolllock",
+	KeyPause:          "pause",
+	KeyInsert:         "insert",
+	KeyHome:           "home",
+	KeyPageUp:         "pageup",
+	KeyDelete:         "delete",
+	KeyEnd:            "end",
+	KeyPageDown:       "pagedown",
+	KeyRight:          "right",
+	KeyLeft:           "left",
+	KeyDown:           "down",
+	KeyUp:             "up",
+	KeyNumLock:        "numlock",
+	KeyPadSlash:       "kpslash",
+	KeyPadAsterisk:    "kpasterisk",
+	KeyPadMinus:       "kpminus",
+	KeyPadPlus:        "kpplus",
+	KeyPadEnter:       "kpenter",
+	KeyPad1:           "kp1",
+	KeyPad2:           "kp2",
+	KeyPad3:           "kp3",
+	KeyPad4:           "kp4",
+	KeyPad5:           "kp5",
+	KeyPad6:           "kp6",
+	KeyPad7:           "kp7",
+	KeyPad8:           "kp8",
+	KeyPad9:           "kp9",
+	KeyPad0:           "kp0",
+	KeyPadPeriod:      "kpperiod",
+	KeyNonUSBackslash: "nonusbackslash",
+	KeyApplication:    "application",
+	KeyLeftCtrl:       "leftctrl",
+	KeyLeftShift:      "leftshift",
+	KeyLeftAlt:        "leftalt",
+	KeyLeftGUI:        "leftgui",
+	KeyRightCtrl:      "rightctrl",
+	KeyRightShift:     "rightshift",
+	KeyRightAlt:       "rightalt",
+	KeyRightGUI:       "rightgui",
+}
+
+// 🐱🐱🐱 Cat keys! 🐱🐱🐱
@@ -0,0 +1,156 @@
+package makcu
+
+// 🐱 One key press on the way to a character: a key pressed while mod is held
+type Stroke struct {
+	Mod Modifier
+	Key Key
+}
+
+// 🐱 A host keyboard layout: which key presses the host turns into which characters. TypeString needs it
+// because the MAKCU sends key positions, not characters.
+type Layout struct {
+	Name  string
+	chars map[rune][]Stroke // one stroke, or a dead key followed by a base key
+}
+
+// 🐱 Returns the key presses that type r on this layout
+func (l *Layout) Strokes(r rune) ([]Stroke, bool) {
+	s, ok := l.chars[r]
+	return s, ok
+}
+
+// 🐱 Reports whether r can be typed on this layout
+func (l *Layout) CanType(r rune) bool {
+	_, ok := l.chars[r]
+	return ok
+}
+
+// 🐱 The layouts TypeString knows out of the box
+var (
+	LayoutUS = buildLayout("US", usRows, nil)
+	LayoutUK = buildLayout("UK", ukRows, nil)
+	LayoutDE = buildLayout("DE", deRows, deDead)
+	LayoutFR = buildLayout("FR", frRows, frDead)
+)
+
+// 🐱🐱🐱 Cat layouts! 🐱🐱🐱
+
+// 🐱 One row of keys and what they type plain, with Shift and with AltGr. A space means nothing.
+type layoutRow struct {
+	keys                 []Key
+	normal, shift, altGr string
+}
+
+var (
+	numberRow = []Key{KeyGrave, Key1, Key2, Key3, Key4, Key5, Key6, Key7, Key8, Key9, Key0, KeyMinus, KeyEqual}
+	topRow    = []Key{KeyQ, KeyW, KeyE, KeyR, KeyT, KeyY, KeyU, KeyI, KeyO, KeyP, KeyLeftBracket, KeyRightBracket}
+	homeRowUS = []Key{KeyA, KeyS, KeyD, KeyF, KeyG, KeyH, KeyJ, KeyK, KeyL, KeySemicolon, KeyApostrophe, KeyBackslash}
+	homeRow   = []Key{KeyA, KeyS, KeyD, KeyF, KeyG, KeyH, KeyJ, KeyK, KeyL, KeySemicolon, KeyApostrophe, KeyNonUSHash}
+	bottomUS  = []Key{KeyZ, KeyX, KeyC, KeyV, KeyB, KeyN, KeyM, KeyComma, KeyPeriod, KeySlash}
+	bottomRow = []Key{KeyNonUSBackslash, KeyZ, KeyX, KeyC, KeyV, KeyB, KeyN, KeyM, KeyComma, KeyPeriod, KeySlash}
+)
+
+var usRows = []layoutRow{
+	{numberRow, "`1234567890-=", "~!@#$%^&*()_+", ""},
+	{topRow, "qwertyuiop[]", "QWERTYUIOP{}", ""},
+	{homeRowUS, "asdfghjkl;'\\", "ASDFGHJKL:\"|", ""},
+	{bottomUS, "zxcvbnm,./", "ZXCVBNM<>?", ""},
+}
+
+var ukRows = []layoutRow{
+	{numberRow, "`1234567890-=", "¬!\"£$%^&*()_+", "¦   €        "},
+	{topRow, "qwertyuiop[]", "QWERTYUIOP{}", "  é   úíó   "},
+	{homeRow, "asdfghjkl;'#", "ASDFGHJKL:@~", "á           "},
+	{bottomRow, "\\zxcvbnm,./", "|ZXCVBNM<>?", ""},
+}
+
+var deRows = []layoutRow{
+	{numberRow, "^1234567890ß´", "°!\"§$%&/()=?`", "  ²³   {[]}\\ "},
+	{topRow, "qwertzuiopü+", "QWERTZUIOPÜ*", "@ €        ~"},
+	{homeRow, "asdfghjklöä#", "ASDFGHJKLÖÄ'", ""},
+	{bottomRow, "<yxcvbnm,.-", ">YXCVBNM;:_", "|      µ   "},
+}
+
+var frRows = []layoutRow{
+	{numberRow, "²&é\"'(-è_çà)=", " 1234567890°+", "  ~#{[|`\\^@]}"},
+	{topRow, "azertyuiop^$", "AZERTYUIOP¨£", "  €        ¤"},
+	{homeRow, "qsdfghjklmù*", "QSDFGHJKLM%µ", ""},
+	{bottomRow, "<wxcvbn,;:!", ">WXCVBN?./§", ""},
+}
+
+// 🐱 Dead keys: pressing one types nothing until the next key, which it puts its accent on
+var (
+	deDead = map[Stroke]rune{
+		{0, KeyGrave}:        '^',
+		{0, KeyEqual}:        '´',
+		{ModShift, KeyEqual}: '`',
+	}
+	frDead = map[Stroke]rune{
+		{0, KeyLeftBracket}:        '^',
+		{ModShift, KeyLeftBracket}: '¨',
+		{ModAltGr, Key2}:           '~',
+		{ModAltGr, Key7}:           '`',
+	}
+)
+
+// 🐱 What each accent makes of the letters it can go on
+var deadCompose = map[rune]map[rune]rune{
+	'^': {'a': 'â', 'e': 'ê', 'i': 'î', 'o': 'ô', 'u': 'û', 'A': 'Â', 'E': 'Ê', 'I': 'Î', 'O': 'Ô', 'U': 'Û'},
+	'´': {'a': 'á', 'e': 'é', 'i': 'í', 'o': 'ó', 'u': 'ú', 'y': 'ý', 'A': 'Á', 'E': 'É', 'I': 'Í', 'O': 'Ó', 'U': 'Ú', 'Y': 'Ý'},
+	'`': {'a': 'à', 'e': 'è', 'i': 'ì', 'o': 'ò', 'u': 'ù', 'A': 'À', 'E': 'È', 'I': 'Ì', 'O': 'Ò', 'U': 'Ù'},
+	'¨': {'a': 'ä', 'e': 'ë', 'i': 'ï', 'o': 'ö', 'u': 'ü', 'y': 'ÿ', 'A': 'Ä', 'E': 'Ë', 'I': 'Ï', 'O': 'Ö', 'U': 'Ü'},
+	'~': {'a': 'ã', 'n': 'ñ', 'o': 'õ', 'A': 'Ã', 'N': 'Ñ', 'O': 'Õ'},
+}
+
+// 🐱 Turns the row tables into a rune -> key presses map. Characters a key types directly win over ones
+// composed with a dead key.
+func buildLayout(name string, rows []layoutRow, dead map[Stroke]rune) *Layout {
+	l := &Layout{Name: name, chars: map[rune][]Stroke{
+		' ':  {{0, KeySpace}},
+		'\n': {{0, KeyEnter}},
+		'\t': {{0, KeyTab}},
+	}}
+
+	deadKey := map[rune]Stroke{}
+	for _, row := range rows {
+		for _, level := range []struct {
+			mod   Modifier
+			chars string
+		}{{0, row.normal}, {ModShift, row.shift}, {ModAltGr, row.altGr}} {
+			for i, r := range []rune(level.chars) {
+				if r == ' ' || i >= len(row.keys) {
+					continue
+				}
+
+				s := Stroke{level.mod, row.keys[i]}
+				if accent, ok := dead[s]; ok {
+					// the accent on its own is the dead key followed by space
+					deadKey[accent] = s
+					s2 := []Stroke{s, {0, KeySpace}}
+					if _, taken := l.chars[accent]; !taken {
+						l.chars[accent] = s2
+					}
+					continue
+				}
+
+				if _, taken := l.chars[r]; !taken {
+					l.chars[r] = []Stroke{s}
+				}
+			}
+		}
+	}
+
+	for accent, dk := range deadKey {
+		for base, composed := range deadCompose[accent] {
+			b, ok := l.chars[base]
+			if _, taken := l.chars[composed]; taken || !ok || len(b) != 1 {
+				continue
+			}
+			l.chars[composed] = []Stroke{dk, b[0]}
+		}
+	}
+
+	return l
+}
+
+// 🐱🐱🐱 Cat layout tables! 🐱🐱🐱
@@ -0,0 +1,73 @@
+package makcu
+
+import "testing"
+
+// 🐱 What each layout has to send for a character, as FormatKeyActions prints it
+func TestLayoutGolden(t *testing.T) {
+	for _, tc := range []struct {
+		layout *Layout
+		r      rune
+		want   string
+	}{
+		{LayoutUS, 'a', "+a -a"},
+		{LayoutUS, 'A', "+leftshift +a -a -leftshift"},
+		{LayoutUS, '@', "+leftshift +2 -2 -leftshift"},
+		{LayoutUS, '\n', "+enter -enter"},
+
+		{LayoutUK, '@', "+leftshift +apostrophe -apostrophe -leftshift"},
+		{LayoutUK, '£', "+leftshift +3 -3 -leftshift"},
+		{LayoutUK, '€', "+rightalt +4 -4 -rightalt"},
+
+		// z and y swap places, @ and € are on AltGr, ^ is a dead key
+		{LayoutDE, 'z', "+y -y"},
+		{LayoutDE, 'y', "+z -z"},
+		{LayoutDE, 'Z', "+leftshift +y -y -leftshift"},
+		{LayoutDE, '@', "+rightalt +q -q -rightalt"},
+		{LayoutDE, '€', "+rightalt +e -e -rightalt"},
+		{LayoutDE, 'ß', "+minus -minus"},
+		{LayoutDE, 'â', "+grave -grave +a -a"},
+		{LayoutDE, 'Ê', "+grave -grave +leftshift +e -e -leftshift"},
+		{LayoutDE, '^', "+grave -grave +space -space"},
+		{LayoutDE, 'é', "+equal -equal +e -e"},
+
+		// the number row types symbols and needs Shift for the digits
+		{LayoutFR, '&', "+1 -1"},
+		{LayoutFR, '1', "+leftshift +1 -1 -leftshift"},
+		{LayoutFR, 'é', "+2 -2"},
+		{LayoutFR, '0', "+leftshift +0 -0 -leftshift"},
+		{LayoutFR, '@', "+rightalt +0 -0 -rightalt"},
+		{LayoutFR, 'a', "+q -q"},
+		{LayoutFR, 'q', "+a -a"},
+		{LayoutFR, 'm', "+semicolon -semicolon"},
+		{LayoutFR, 'ê', "+leftbracket -leftbracket +e -e"},
+		{LayoutFR, 'ë', "+leftshift +leftbracket -leftbracket -leftshift +e -e"},
+	} {
+		seq, err := typeActions(nil, string(tc.r), tc.layout)
+		if err != nil {
+			t.Errorf("%s %q: %v", tc.layout.Name, tc.r, err)
+			continue
+		}
+
+		if got := FormatKeyActions(seq); got != tc.want {
+			t.Errorf("%s %q = %q, want %q", tc.layout.Name, tc.r, got, tc.want)
+		}
+	}
+}
+
+func TestLayoutCantType(t *testing.T) {
+	for _, tc := range []struct {
+		layout *Layout
+		r      rune
+	}{
+		{LayoutUS, 'é'},
+		{LayoutUS, '€'},
+		{LayoutDE, 'ñ'},
+		{LayoutFR, 'ß'},
+		{LayoutUK, '日'},
+	} {
+		if tc.layout.CanType(tc.r) {
+			strokes, _ := tc.layout.Strokes(tc.r)
+			t.Errorf("%s types %q as %v", tc.layout.Name, tc.r, strokes)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package makcu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 🐱 Parts of the physical mouse that can be kept from reaching the host. Combine them with |.
+type LockTarget uint16
+
+const (
+	LockX LockTarget = 1 << iota
+	LockY
+	LockLeft
+	LockRight
+	LockMiddle
+	LockSide1
+	LockSide2
+	LockWheel
+
+	LockAxes    = LockX | LockY
+	LockButtons = LockLeft | LockRight | LockMiddle | LockSide1 | LockSide2
+	LockAll     = LockAxes | LockButtons | LockWheel
+)
+
+// 🐱 Firmware function behind each target, in bit order
+var lockTargets = []struct {
+	target LockTarget
+	name   string
+	fn     string
+}{
+	{LockX, "x", "lock_mx"},
+	{LockY, "y", "lock_my"},
+	{LockLeft, "left", "lock_ml"},
+	{LockRight, "right", "lock_mr"},
+	{LockMiddle, "middle", "lock_mm"},
+	{LockSide1, "side1", "lock_ms1"},
+	{LockSide2, "side2", "lock_ms2"},
+	{LockWheel, "wheel", "lock_mw"},
+}
+
+func (t LockTarget) String() string {
+	var names []string
+	for _, l := range lockTargets {
+		if t&l.target != 0 {
+			names = append(names, l.name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "|")
+}
+
+// 🐱 Lock target for one button
+func (b Button) LockTarget() LockTarget {
+	if !b.valid() {
+		return 0
+	}
+
+	return LockLeft << (b - 1)
+}
+
+// Keeps the given parts of the user's physical mouse from reaching the host, until Unlock. Commands sent through
+// this library are not affected. All targets are locked in a single write.
+func (m *MakcuHandle) Lock(target LockTarget) error {
+	if m == nil {
+		return fmt.Errorf("Lock: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.setLockLocked("Lock", target, true)
+}
+
+// 🐱 Lets the given parts of the physical mouse through again
+func (m *MakcuHandle) Unlock(target LockTarget) error {
+	if m == nil {
+		return fmt.Errorf("Unlock: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.setLockLocked("Unlock", target, false)
+}
+
+// 🐱 Returns what this handle currently has locked
+func (m *MakcuHandle) Locked() LockTarget {
+	if m == nil {
+		return 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.locked
+}
+
+// Locks target, runs fn and unlocks again, whether fn returns, fails or panics (the panic carries on after the
+// unlock). Targets that were already locked before WithLock was called stay locked, so calls can be nested.
+// Closing the handle unlocks everything too, so the user's mouse is never left frozen.
+//
+//	err := MakcuConn.WithLock(makcu.LockAxes, func() error {
+//		return MakcuConn.MoveMouseWithCurve(300, 0, 30)
+//	})
+func (m *MakcuHandle) WithLock(target LockTarget, fn func() error) (err error) {
+	if m == nil {
+		return fmt.Errorf("WithLock: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	mine := target &^ m.locked
+	err = m.setLockLocked("WithLock", mine, true)
+	if err != nil {
+		// undo whatever part of it got through
+		_ = m.setLockLocked("WithLock", mine&m.locked, false)
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		// Close may have unlocked (and forgotten) them already
+		if uerr := m.setLockLocked("WithLock", mine&m.locked, false); uerr != nil && err == nil {
+			err = uerr
+		}
+	}()
+
+	return fn()
+}
+
+// 🐱🐱🐱 Cat lock! 🐱🐱🐱
+
+// 🐱 Sends the lock or unlock command for every target in one write and remembers the new state
+func (m *MakcuHandle) setLockLocked(op string, target LockTarget, locked bool) error {
+	if target&^LockAll != 0 {
+		return fmt.Errorf("%s: unknown lock target 0x%X", op, uint16(target))
+	}
+
+	if target == 0 {
+		return nil
+	}
+
+	if err := m.require(op, CapLock); err != nil {
+		return err
+	}
+
+	state := 0
+	if locked {
+		state = 1
+	}
+
+	m.cmdBuf = m.cmdBuf[:0]
+	for _, l := range lockTargets {
+		if target&l.target != 0 {
+			m.cmdBuf = appendCall(m.cmdBuf, l.fn, state)
+		}
+	}
+
+	// marked before sending, so if the write fails part way Close still unlocks whatever did get locked
+	if locked {
+		m.locked |= target
+	}
+
+	if err := m.execLocked(m.cmdBuf); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !locked {
+		m.locked &^= target
+	}
+
+	return nil
+}
+
+// 🐱🐱🐱 Cat lock state! 🐱🐱🐱
@@ -0,0 +1,88 @@
+package makcu
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCloseUnlocks(t *testing.T) {
+	m, dev := openFake(t, "")
+	if err := m.Lock(LockX | LockLeft); err != nil {
+		t.Fatal(err)
+	}
+
+	dev.Reset()
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := callNames(dev), []string{"LockX(0)", "LockLeft(0)"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Close sent %v, want %v", got, want)
+	}
+}
+
+func TestWithLockUnlocksOnError(t *testing.T) {
+	m, dev := openFake(t, "")
+	if err := m.Lock(LockY); err != nil {
+		t.Fatal(err)
+	}
+
+	boom := errors.New("boom")
+	err := m.WithLock(LockY|LockWheel, func() error { return boom })
+	if !errors.Is(err, boom) {
+		t.Fatalf("WithLock = %v", err)
+	}
+
+	// LockY was already held by the caller, so only the wheel lock WithLock took is undone
+	if m.Locked() != LockY {
+		t.Errorf("Locked() = %s, want %s", m.Locked(), LockY)
+	}
+
+	calls := callNames(dev)
+	if calls[len(calls)-1] != "LockWheel(0)" {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+// 🐱 ChangeBaudRate must not leave the physical mouse frozen: once the set baud frame is out nothing may be sent
+// at the old rate, so the locks go to the new handle instead of being undone by the old one's Close
+func TestSwitchBaudHandsOverLocks(t *testing.T) {
+	m, _ := openFake(t, "")
+	if err := m.Lock(LockX | LockLeft); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.SubscribeButtons(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := NewTrace(0)
+	m.SetTrace(tr)
+
+	locked, err := m.switchBaud(4000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if locked != LockX|LockLeft {
+		t.Errorf("handed over %s, want %s", locked, LockX|LockLeft)
+	}
+
+	var tx [][]byte
+	for _, e := range tr.Entries() {
+		if e.Dir == TX {
+			tx = append(tx, e.Data)
+		}
+	}
+
+	if len(tx) == 0 || !bytes.Equal(tx[len(tx)-1], setBaudFrame(4000000)) {
+		t.Fatalf("the set baud frame wasn't the last thing sent: %q", tx)
+	}
+
+	if !bytes.Contains(bytes.Join(tx, nil), []byte("km.buttons(0)")) {
+		t.Errorf("the button stream wasn't turned off before the switch: %q", tx)
+	}
+}
@@ -1,648 +1,906 @@
-package makcu
-
-//newest pdate
-// 🐱 Imports
-import (
-	"fmt"
-	"log/slog"
-	"os"
-	"regexp"
-	"strings"
-	"syscall"
-	"time"
-	"unsafe"
-
-	"golang.org/x/sys/windows"
-	"golang.org/x/sys/windows/registry"
-)
-
-// 🐱 Debug flag
-var Debug bool = false
-
-// 🐱 Logger instance
-var logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
-
-func DebugPrint(s string, a ...interface{}) {
-	if Debug {
-		logger.Debug(fmt.Sprintf("🐱 "+s, a...))
-	}
-}
-
-// 🐱🐱🐱 Cat debug function! 🐱🐱🐱
-
-func InfoPrint(s string, a ...interface{}) {
-	logger.Info(fmt.Sprintf("🐱🟢 "+s, a...))
-}
-
-// 🐱🐱🐱 Cat info function! 🐱🐱🐱
-
-func ErrorPrint(s string, a ...interface{}) {
-	logger.Error(fmt.Sprintf("🐱🔴 "+s, a...))
-}
-
-// 🐱 Converts UTF16 buffer to string
-func utf16ToString(buf []uint16) string {
-	for i, v := range buf {
-		if v == 0 {
-			return syscall.UTF16ToString(buf[:i])
-		}
-	}
-	return syscall.UTF16ToString(buf)
-}
-
-// 🐱🐱🐱 Cat string conversion! 🐱🐱🐱
-
-// 🐱 Gets device info
-func GetDeviceInfo(h, devInfo unsafe.Pointer, getProp *syscall.LazyProc, propertyCode uint32) string {
-	buf := make([]uint16, 512)
-	getProp.Call(uintptr(h), uintptr(devInfo), uintptr(propertyCode), 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)*2), 0)
-	return utf16ToString(buf)
-}
-
-// 🐱🐱🐱 Cat device info! 🐱🐱🐱
-
-// 🐱 Gets port name from registry
-func GetPortName(hDevInfo uintptr, devInfoData *byte) (string, error) {
-	setupapi := syscall.NewLazyDLL("setupapi.dll")
-	setupDiOpenDevRegKey := setupapi.NewProc("SetupDiOpenDevRegKey")
-
-	regKey, _, err := setupDiOpenDevRegKey.Call(hDevInfo, uintptr(unsafe.Pointer(devInfoData)), 0x00000001, 0, 0x00000001, 0x20019)
-	if regKey == 0 || regKey == ^uintptr(0) {
-		return "", fmt.Errorf("GetPortName: failed to open device registry key: %w", err)
-	}
-
-	defer syscall.RegCloseKey(syscall.Handle(regKey))
-
-	key := registry.Key(regKey)
-	portName, _, err := key.GetStringValue("PortName")
-	if err != nil {
-		return "", fmt.Errorf("GetPortName: failed to get 'PortName' value: %w", err)
-	}
-
-	return portName, nil
-}
-
-// 🐱🐱🐱 Cat port name! 🐱🐱🐱
-
-// 🐱 Device property constants
-const (
-	DeviceDescription = 0x0 // Device Description (ex: USB-Enhanced-SERIAL CH343)
-	HardwareID        = 0x1 // Hardware ID (ex: USB\VID_1A86&PID_55D3&REV_0445)
-	DeviceName        = 0xC // Friendly name of the device (ex: USB-Enhanced-SERIAL CH343 (COM3) )
-)
-
-// Find searches for the MAKCU device by default name or VID/PID and returns the COM port.
-func Find() (string, error) {
-	setupapi := syscall.NewLazyDLL("setupapi.dll")
-	getClassDevs := setupapi.NewProc("SetupDiGetClassDevsW")
-	enumDeviceInfo := setupapi.NewProc("SetupDiEnumDeviceInfo")
-	getDeviceProperty := setupapi.NewProc("SetupDiGetDeviceRegistryPropertyW")
-	destroyDeviceList := setupapi.NewProc("SetupDiDestroyDeviceInfoList")
-
-	guid := windows.GUID{0x4d36e978, 0xe325, 0x11ce, [8]byte{0xbf, 0xc1, 0x08, 0x00, 0x2b, 0xe1, 0x03, 0x18}}
-	h, _, _ := getClassDevs.Call(uintptr(unsafe.Pointer(&guid)), 0, 0, uintptr(0x2))
-	if h == 0 || h == ^uintptr(0) {
-		DebugPrint("Failed to get device list\n")
-		return "", fmt.Errorf("Find: failed to get device list")
-	}
-
-	defer func() {
-		ret, _, _ := destroyDeviceList.Call(h)
-		if ret == 0 {
-			ErrorPrint("Failed to destroy device info list handle")
-		}
-	}()
-
-	// 🐱🐱🐱 Cat device search! 🐱🐱🐱
-
-	for index := 0; ; index++ {
-		var devInfo struct {
-			cbSize    uint32
-			ClassGuid windows.GUID
-			DevInst   uint32
-			Reserved  uintptr
-		}
-
-		devInfo.cbSize = uint32(unsafe.Sizeof(devInfo))
-
-		ok, _, _ := enumDeviceInfo.Call(h, uintptr(index), uintptr(unsafe.Pointer(&devInfo)))
-		if ok == 0 {
-			break
-		}
-
-		description := GetDeviceInfo(unsafe.Pointer(h), unsafe.Pointer(&devInfo), getDeviceProperty, DeviceDescription)
-		hwid := GetDeviceInfo(unsafe.Pointer(h), unsafe.Pointer(&devInfo), getDeviceProperty, HardwareID)
-		deviceNameStr := GetDeviceInfo(unsafe.Pointer(h), unsafe.Pointer(&devInfo), getDeviceProperty, DeviceName)
-
-		if deviceNameStr == "" || description == "" || hwid == "" {
-			continue
-		}
-
-		if strings.Contains(deviceNameStr, "USB-Enhanced-SERIAL CH343") || strings.Contains(hwid, "VID_1A86&PID_55D3") {
-			DebugPrint("--------\n")
-			DebugPrint("Name: %s\n", deviceNameStr)
-			DebugPrint("Description: %s\n", description)
-			DebugPrint("Hardware Info: %s\n", hwid)
-
-			port := regexp.MustCompile(`COM\d+`).FindString(deviceNameStr) //creds to yrlu for this idea lawl
-			if port != "" {
-				DebugPrint("Port Name: %s\n", port)
-				DebugPrint("--------\n")
-				return port, nil
-			}
-
-			// Try to get port from registry if not found in name
-			port, err := GetPortName(h, (*byte)(unsafe.Pointer(&devInfo)))
-			if err != nil {
-				DebugPrint("Failed to get port name: %v\n", err)
-				return "", fmt.Errorf("Find: failed to get port name from registry: %w", err)
-			}
-
-			DebugPrint("Port Name: %s\n", port)
-			DebugPrint("--------\n")
-
-			if strings.Contains(port, "COM") {
-				return port, nil
-			}
-
-			return "", nil
-		}
-	}
-
-	fmt.Println("Failed to locate MAKCU!")
-	return "", fmt.Errorf("Find: device not found")
-}
-
-// Sets the timeout settings for the COM port
-func SetTimeouts(handle windows.Handle) error {
-	kernel32 := syscall.NewLazyDLL("kernel32.dll")
-	setCommTimeouts := kernel32.NewProc("SetCommTimeouts")
-
-	var timeouts windows.CommTimeouts
-	timeouts.ReadIntervalTimeout = 50         // Time to wait for a byte to arrive
-	timeouts.ReadTotalTimeoutMultiplier = 10  // Time to wait before a read operation is finished
-	timeouts.ReadTotalTimeoutConstant = 500   // Timeout in milliseconds for the entire read operation
-	timeouts.WriteTotalTimeoutMultiplier = 10 // Timeout for writing
-	timeouts.WriteTotalTimeoutConstant = 500  // Timeout in milliseconds for the entire write operation
-
-	ret, _, err := setCommTimeouts.Call(uintptr(handle), uintptr(unsafe.Pointer(&timeouts)))
-	if ret == 0 {
-		return fmt.Errorf("SetTimeouts: SetCommTimeouts failed: %w", err)
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat timeouts! 🐱🐱🐱
-
-// 🐱 Handle for MAKCU device
-type MakcuHandle struct {
-	Port   string
-	handle windows.Handle
-	dcb    windows.DCB
-}
-
-// Make a connection to the COM port where our MAKCU was found.
-func Connect(portName string, baudRate uint32) (*MakcuHandle, error) {
-	kernel32 := syscall.NewLazyDLL("kernel32.dll")
-	openPort := kernel32.NewProc("CreateFileW")
-	setCommState := kernel32.NewProc("SetCommState")
-
-	if !strings.HasPrefix(portName, `\\.\`) {
-		portName = `\\.\` + portName
-	}
-
-	path, err := windows.UTF16PtrFromString(portName)
-	if err != nil {
-		return nil, fmt.Errorf("Connect: failed to convert port name to UTF16: %w", err)
-	}
-
-	handle, _, err := openPort.Call(uintptr(unsafe.Pointer(path)), syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, 0, 3, syscall.FILE_ATTRIBUTE_NORMAL, 0)
-	if handle == uintptr(syscall.InvalidHandle) {
-		return nil, fmt.Errorf("Connect: failed to open port: %w", err)
-	}
-
-	portHandle := windows.Handle(handle)
-	// set the settings for the serial communications
-	dcbOpts := &windows.DCB{}
-	dcbOpts.DCBlength = uint32(unsafe.Sizeof(*dcbOpts))
-	dcbOpts.BaudRate = baudRate
-	dcbOpts.Flags = 0
-	dcbOpts.ByteSize = 8
-	dcbOpts.Parity = 0
-	dcbOpts.StopBits = 1
-
-	dcbOpts.Flags |= 0x00000400
-	dcbOpts.Flags |= 0x00000800
-
-	ret, _, err := setCommState.Call(uintptr(portHandle), uintptr(unsafe.Pointer(dcbOpts)))
-	if ret == 0 {
-		return nil, fmt.Errorf("Connect: failed to set communication state: %w", err)
-	}
-
-	err = SetTimeouts(portHandle)
-	if err != nil {
-		return nil, fmt.Errorf("Connect: failed to set timeouts: %w", err)
-	}
-
-	CleanPort := strings.TrimPrefix(portName, `\\.\`)
-	DebugPrint("Successfully Connected to MAKCU! {Port %s | Baud Rate %d}\n", CleanPort, baudRate)
-
-	return &MakcuHandle{
-		Port:   CleanPort,
-		handle: portHandle,
-		dcb:    *dcbOpts,
-	}, nil
-}
-
-// 🐱🐱🐱 Cat connect! 🐱🐱🐱
-
-// Close the connection to the MAKCU
-func (m *MakcuHandle) Close() error {
-	if m == nil {
-		return fmt.Errorf("Close: MakcuHandle is nil (no device connected)")
-	}
-
-	err := windows.CloseHandle(m.handle)
-	if err != nil {
-		return fmt.Errorf("Close: failed to close handle: %w", err)
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat close! 🐱🐱🐱
-
-// Sends the bytes needed to change the Baud Rate of the MAKCU to 4m and then returns a new Connection object with the new baud rate
-// Note: This is NOT a permanent change and will reset back to the default 115200 baud rate after the MAKCU powers off and then back on again.
-func ChangeBaudRate(m *MakcuHandle) (*MakcuHandle, error) {
-	if m == nil {
-		return nil, fmt.Errorf("ChangeBaudRate: MakcuHandle is nil (no device connected)")
-	}
-
-	n, err := m.Write([]byte{0xDE, 0xAD, 0x05, 0x00, 0xA5, 0x00, 0x09, 0x3D, 0x00})
-	if err != nil {
-		// Always try to close the handle on error
-		_ = m.Close()
-		return nil, fmt.Errorf("ChangeBaudRate: write error: %w", err)
-	}
-
-	if n != 9 {
-		_ = m.Close()
-		return nil, fmt.Errorf("ChangeBaudRate: wrong number of bytes written (got %d, want 9)", n)
-	}
-
-	if err := m.Close(); err != nil {
-		ErrorPrint("ChangeBaudRate: failed to close old connection: %v", err)
-		// Continue, but log the error
-	}
-
-	NewConn, err := Connect(m.Port, 4000000)
-	if err != nil {
-		return nil, fmt.Errorf("ChangeBaudRate: connect error: %w", err)
-	}
-
-	time.Sleep(1 * time.Second)
-
-	_, err = NewConn.Write([]byte("km.version()\r"))
-	if err != nil {
-		_ = NewConn.Close()
-		return nil, fmt.Errorf("ChangeBaudRate: write error after reconnect: %w", err)
-	}
-
-	ReadBuf := make([]byte, 32)
-	n, err = NewConn.Read(ReadBuf)
-	if err != nil {
-		_ = NewConn.Close()
-		return nil, fmt.Errorf("ChangeBaudRate: read error after reconnect: %w", err)
-	}
-
-	if !strings.Contains(string(ReadBuf[:n]), "MAKCU") {
-		_ = NewConn.Close()
-		return nil, fmt.Errorf("ChangeBaudRate: did not receive expected response, got: %q", string(ReadBuf[:n]))
-	}
-
-	time.Sleep(1 * time.Second)
-
-	DebugPrint("Successfully Changed Baud Rate To %d!\n", 4000000)
-
-	return NewConn, nil
-}
-
-// 🐱🐱🐱 Cat baud rate! 🐱🐱🐱
-
-// Sends the given bytes to the MAKCU and returns the number of bytes written.
-func (m *MakcuHandle) Write(data []byte) (int, error) {
-	if m == nil {
-		return -1, fmt.Errorf("Write: MakcuHandle is nil (no device connected)")
-	}
-
-	var bytesWritten uint32
-	kernel32 := syscall.NewLazyDLL("kernel32.dll")
-	writeFile := kernel32.NewProc("WriteFile")
-
-	var overlapped windows.Overlapped
-	overlapped.Offset = 0
-	overlapped.OffsetHigh = 0
-
-	DebugPrint("Sending %s\r\n", data[:])
-
-	ret, _, err := writeFile.Call(uintptr(m.handle), uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)), uintptr(unsafe.Pointer(&bytesWritten)), uintptr(unsafe.Pointer(&overlapped)))
-	if ret == 0 {
-		return -1, fmt.Errorf("Write: error writing to port: %w", err)
-	}
-
-	return int(bytesWritten), nil
-}
-
-// 🐱🐱🐱 Cat write! 🐱🐱🐱
-
-// Reads data from the MAKCU and saves it to a given buffer then returns the number of bytes read.
-func (m *MakcuHandle) Read(buffer []byte) (int, error) {
-	if m == nil {
-		return -1, fmt.Errorf("Write: MakcuHandle is nil (no device connected)")
-	}
-
-	kernel32 := syscall.NewLazyDLL("kernel32.dll")
-	readFile := kernel32.NewProc("ReadFile")
-
-	var bytesRead uint32
-	ret, _, err := readFile.Call(uintptr(m.handle), uintptr(unsafe.Pointer(&buffer[0])), uintptr(len(buffer)), uintptr(unsafe.Pointer(&bytesRead)), 0)
-	if ret == 0 {
-		return -1, fmt.Errorf("Read: error reading from port: %w", err)
-	}
-
-	return int(bytesRead), nil
-}
-
-// 🐱🐱🐱 Cat read! 🐱🐱🐱
-
-// 🐱 Mouse left down
-func (m *MakcuHandle) LeftDown() error {
-	if m == nil {
-		return fmt.Errorf("LeftDown: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.left(1)\r"))
-	if err != nil {
-		DebugPrint("Failed to press mouse: Write Error: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat left down! 🐱🐱🐱
-
-// 🐱 Mouse left up
-func (m *MakcuHandle) LeftUp() error {
-	if m == nil {
-		return fmt.Errorf("LeftUp: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.left(0)\r"))
-	if err != nil {
-		DebugPrint("Failed to release mouse: Write Error: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat left up! 🐱🐱🐱
-
-// 🐱 Mouse left click
-func (m *MakcuHandle) LeftClick() error {
-	if m == nil {
-		return fmt.Errorf("LeftClick: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.left(1)\r km.left(0)\r"))
-	if err != nil {
-		DebugPrint("Failed to click mouse: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat left click! 🐱🐱🐱
-
-// 🐱 Mouse right down
-func (m *MakcuHandle) RightDown() error {
-	if m == nil {
-		return fmt.Errorf("RightDown: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.right(1)\r"))
-	if err != nil {
-		DebugPrint("Failed to press mouse: Write Error: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat right down! 🐱🐱🐱
-
-// 🐱 Mouse right up
-func (m *MakcuHandle) RightUp() error {
-	if m == nil {
-		return fmt.Errorf("RightUp: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.right(0)\r"))
-	if err != nil {
-		DebugPrint("Failed to release mouse: Write Error: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat right up! 🐱🐱🐱
-
-// 🐱 Mouse right click
-func (m *MakcuHandle) RightClick() error {
-	if m == nil {
-		return fmt.Errorf("RightClick: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.right(1)\r km.right(0)\r"))
-	if err != nil {
-		DebugPrint("Failed to right click mouse: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat right click! 🐱🐱🐱
-
-// 🐱 Mouse middle down
-func (m *MakcuHandle) MiddleDown() error {
-	if m == nil {
-		return fmt.Errorf("MiddleDown: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.middle(1)\r"))
-	if err != nil {
-		DebugPrint("Failed to press middle mouse button: Write Error: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat middle down! 🐱🐱🐱
-
-// 🐱 Mouse middle up
-func (m *MakcuHandle) MiddleUp() error {
-	if m == nil {
-		return fmt.Errorf("MiddleUp: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.middle(0)\r"))
-	if err != nil {
-		DebugPrint("Failed to release middle mouse button: Write Error: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat middle up 🖕! 🐱🐱🐱
-
-// 🐱 Mouse middle click
-func (m *MakcuHandle) MiddleClick() error {
-	if m == nil {
-		return fmt.Errorf("MiddleClick: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte("km.middle(1)\r km.middle(0)\r"))
-	if err != nil {
-		DebugPrint("Failed to middle click mouse: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat middle click! 🐱🐱🐱
-
-// 🐱 Mouse button constants
-const (
-	MOUSE_BUTTON_LEFT   = 1
-	MOUSE_BUTTON_RIGHT  = 2
-	MOUSE_BUTTON_MIDDLE = 3
-)
-
-// 🐱 Clicks a mouse button
-func (m *MakcuHandle) Click(i int, delay time.Duration) error {
-	if m == nil {
-		return fmt.Errorf("Click: MakcuHandle is nil (no device connected)")
-	}
-
-	// Basically, we create a function pointer which is just basically a variable that stores a function for us.
-	// Then we can use that variable to call the function later on. :()
-	type mouseAction func() error
-	var down, up mouseAction
-
-	switch i {
-	case MOUSE_BUTTON_LEFT:
-		down, up = m.LeftDown, m.LeftUp
-	case MOUSE_BUTTON_RIGHT:
-		down, up = m.RightDown, m.RightUp
-	case MOUSE_BUTTON_MIDDLE:
-		down, up = m.MiddleDown, m.MiddleUp
-	default:
-		return fmt.Errorf("invalid mouse button: %d", i)
-	}
-
-	if err := down(); err != nil {
-		return err
-	}
-
-	if delay > 0 {
-		time.Sleep(delay)
-	}
-
-	if err := up(); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (m *MakcuHandle) ClickMouse() error {
-	if m == nil {
-		return fmt.Errorf("ClickMouse: MakcuHandle is nil (no device connected)")
-	}
-
-	return m.Click(MOUSE_BUTTON_LEFT, 0)
-}
-
-// 🐱🐱🐱 Cat click! 🐱🐱🐱
-
-// 🐱 Scrolls the mouse
-func (m *MakcuHandle) ScrollMouse(amount int) error {
-	if m == nil {
-		return fmt.Errorf("ScrollMouse: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte(fmt.Sprintf("km.wheel(%d)\r", amount)))
-	if err != nil {
-		DebugPrint("Failed to scroll mouse: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat scroll! 🐱🐱🐱
-
-// 🐱 Moves the mouse
-func (m *MakcuHandle) MoveMouse(x, y int) error {
-	if m == nil {
-		return fmt.Errorf("MoveMouse: MakcuHandle is nil (no device connected)")
-	}
-
-	_, err := m.Write([]byte(fmt.Sprintf("km.move(%d, %d)\r", x, y)))
-	if err != nil {
-		DebugPrint("Failed to move mouse: Write Error: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-// 🐱🐱🐱 Cat move! 🐱🐱🐱
-
-// use a curve with the built in curve functionality from MAKCU... i THINK this is only on fw v3+ ??? idk don't care to fact check it rn either :)
-// "It is common sense that the higher the number of the third parameter, the smoother the curve will be fitted" - from MAKCU/km box docs
-func (m *MakcuHandle) MoveMouseWithCurve(x, y int, params ...int) error {
-	if m == nil {
-		return fmt.Errorf("MoveMouseWithCurve: MakcuHandle is nil (no device connected)")
-	}
-
-	var cmd string
-	switch len(params) {
-	case 0:
-		cmd = fmt.Sprintf("km.move(%d, %d)\r", x, y)
-	case 1:
-		cmd = fmt.Sprintf("km.move(%d, %d, %d)\r", x, y, params[0])
-	case 3:
-		cmd = fmt.Sprintf("km.move(%d, %d, %d, %d, %d)\r", x, y, params[0], params[1], params[2])
-	default:
-		DebugPrint("Invalid number of parameters")
-		return fmt.Errorf("invalid number of parameters")
-	}
-
-	_, err := m.Write([]byte(cmd))
-	if err != nil {
-		DebugPrint("Failed to move mouse with curve: Write Error: %v", err)
-		return err
-	}
-
-	return nil
-}
+package makcu
+
+//newest pdate
+// 🐱 Imports
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// 🐱 Debug flag
+var Debug bool = false
+
+// 🐱 Logger instance
+var logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+func DebugPrint(s string, a ...interface{}) {
+	if Debug {
+		logger.Debug(fmt.Sprintf("🐱 "+s, a...))
+	}
+}
+
+// 🐱🐱🐱 Cat debug function! 🐱🐱🐱
+
+func InfoPrint(s string, a ...interface{}) {
+	logger.Info(fmt.Sprintf("🐱🟢 "+s, a...))
+}
+
+// 🐱🐱🐱 Cat info function! 🐱🐱🐱
+
+func ErrorPrint(s string, a ...interface{}) {
+	logger.Error(fmt.Sprintf("🐱🔴 "+s, a...))
+}
+
+// 🐱 Converts UTF16 buffer to string
+func utf16ToString(buf []uint16) string {
+	for i, v := range buf {
+		if v == 0 {
+			return syscall.UTF16ToString(buf[:i])
+		}
+	}
+	return syscall.UTF16ToString(buf)
+}
+
+// 🐱🐱🐱 Cat string conversion! 🐱🐱🐱
+
+// 🐱 Gets device info
+func GetDeviceInfo(h, devInfo unsafe.Pointer, getProp *syscall.LazyProc, propertyCode uint32) string {
+	buf := make([]uint16, 512)
+	getProp.Call(uintptr(h), uintptr(devInfo), uintptr(propertyCode), 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)*2), 0)
+	return utf16ToString(buf)
+}
+
+// 🐱🐱🐱 Cat device info! 🐱🐱🐱
+
+// 🐱 Gets port name from registry
+func GetPortName(hDevInfo uintptr, devInfoData *byte) (string, error) {
+	setupapi := syscall.NewLazyDLL("setupapi.dll")
+	setupDiOpenDevRegKey := setupapi.NewProc("SetupDiOpenDevRegKey")
+
+	regKey, _, err := setupDiOpenDevRegKey.Call(hDevInfo, uintptr(unsafe.Pointer(devInfoData)), 0x00000001, 0, 0x00000001, 0x20019)
+	if regKey == 0 || regKey == ^uintptr(0) {
+		return "", fmt.Errorf("GetPortName: failed to open device registry key: %w", err)
+	}
+
+	defer syscall.RegCloseKey(syscall.Handle(regKey))
+
+	key := registry.Key(regKey)
+	portName, _, err := key.GetStringValue("PortName")
+	if err != nil {
+		return "", fmt.Errorf("GetPortName: failed to get 'PortName' value: %w", err)
+	}
+
+	return portName, nil
+}
+
+// 🐱🐱🐱 Cat port name! 🐱🐱🐱
+
+// 🐱 Device property constants
+const (
+	DeviceDescription = 0x0 // Device Description (ex: USB-Enhanced-SERIAL CH343)
+	HardwareID        = 0x1 // Hardware ID (ex: USB\VID_1A86&PID_55D3&REV_0445)
+	DeviceName        = 0xC // Friendly name of the device (ex: USB-Enhanced-SERIAL CH343 (COM3) )
+)
+
+// Find searches for the MAKCU device by default name or VID/PID and returns the COM port.
+func Find() (string, error) {
+	setupapi := syscall.NewLazyDLL("setupapi.dll")
+	getClassDevs := setupapi.NewProc("SetupDiGetClassDevsW")
+	enumDeviceInfo := setupapi.NewProc("SetupDiEnumDeviceInfo")
+	getDeviceProperty := setupapi.NewProc("SetupDiGetDeviceRegistryPropertyW")
+	destroyDeviceList := setupapi.NewProc("SetupDiDestroyDeviceInfoList")
+
+	guid := windows.GUID{0x4d36e978, 0xe325, 0x11ce, [8]byte{0xbf, 0xc1, 0x08, 0x00, 0x2b, 0xe1, 0x03, 0x18}}
+	h, _, _ := getClassDevs.Call(uintptr(unsafe.Pointer(&guid)), 0, 0, uintptr(0x2))
+	if h == 0 || h == ^uintptr(0) {
+		DebugPrint("Failed to get device list\n")
+		return "", fmt.Errorf("Find: failed to get device list")
+	}
+
+	defer func() {
+		ret, _, _ := destroyDeviceList.Call(h)
+		if ret == 0 {
+			ErrorPrint("Failed to destroy device info list handle")
+		}
+	}()
+
+	// 🐱🐱🐱 Cat device search! 🐱🐱🐱
+
+	for index := 0; ; index++ {
+		var devInfo struct {
+			cbSize    uint32
+			ClassGuid windows.GUID
+			DevInst   uint32
+			Reserved  uintptr
+		}
+
+		devInfo.cbSize = uint32(unsafe.Sizeof(devInfo))
+
+		ok, _, _ := enumDeviceInfo.Call(h, uintptr(index), uintptr(unsafe.Pointer(&devInfo)))
+		if ok == 0 {
+			break
+		}
+
+		description := GetDeviceInfo(unsafe.Pointer(h), unsafe.Pointer(&devInfo), getDeviceProperty, DeviceDescription)
+		hwid := GetDeviceInfo(unsafe.Pointer(h), unsafe.Pointer(&devInfo), getDeviceProperty, HardwareID)
+		deviceNameStr := GetDeviceInfo(unsafe.Pointer(h), unsafe.Pointer(&devInfo), getDeviceProperty, DeviceName)
+
+		if deviceNameStr == "" || description == "" || hwid == "" {
+			continue
+		}
+
+		if strings.Contains(deviceNameStr, "USB-Enhanced-SERIAL CH343") || strings.Contains(hwid, "VID_1A86&PID_55D3") {
+			DebugPrint("--------\n")
+			DebugPrint("Name: %s\n", deviceNameStr)
+			DebugPrint("Description: %s\n", description)
+			DebugPrint("Hardware Info: %s\n", hwid)
+
+			port := regexp.MustCompile(`COM\d+`).FindString(deviceNameStr) //creds to yrlu for this idea lawl
+			if port != "" {
+				DebugPrint("Port Name: %s\n", port)
+				DebugPrint("--------\n")
+				return port, nil
+			}
+
+			// Try to get port from registry if not found in name
+			port, err := GetPortName(h, (*byte)(unsafe.Pointer(&devInfo)))
+			if err != nil {
+				DebugPrint("Failed to get port name: %v\n", err)
+				return "", fmt.Errorf("Find: failed to get port name from registry: %w", err)
+			}
+
+			DebugPrint("Port Name: %s\n", port)
+			DebugPrint("--------\n")
+
+			if strings.Contains(port, "COM") {
+				return port, nil
+			}
+
+			return "", fmt.Errorf("Find: %w (registry port name %q is not a COM port)", ErrDeviceNotFound, port)
+		}
+	}
+
+	fmt.Println("Failed to locate MAKCU!")
+	return "", fmt.Errorf("Find: %w", ErrDeviceNotFound)
+}
+
+// Sets the timeout settings for the COM port
+func SetTimeouts(handle windows.Handle) error {
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	setCommTimeouts := kernel32.NewProc("SetCommTimeouts")
+
+	var timeouts windows.CommTimeouts
+	timeouts.ReadIntervalTimeout = 50         // Time to wait for a byte to arrive
+	timeouts.ReadTotalTimeoutMultiplier = 10  // Time to wait before a read operation is finished
+	timeouts.ReadTotalTimeoutConstant = 500   // Timeout in milliseconds for the entire read operation
+	timeouts.WriteTotalTimeoutMultiplier = 10 // Timeout for writing
+	timeouts.WriteTotalTimeoutConstant = 500  // Timeout in milliseconds for the entire write operation
+
+	ret, _, err := setCommTimeouts.Call(uintptr(handle), uintptr(unsafe.Pointer(&timeouts)))
+	if ret == 0 {
+		return fmt.Errorf("SetTimeouts: SetCommTimeouts failed: %w", err)
+	}
+
+	return nil
+}
+
+// 🐱🐱🐱 Cat timeouts! 🐱🐱🐱
+
+// 🐱 Handle for MAKCU device
+type MakcuHandle struct {
+	Port     string
+	BaudRate uint32             // baud rate the port is currently running at (the detected one when auto-detecting)
+	Firmware FirmwareVersion    // filled in by ConnectAuto, ChangeBaudRate and Version(). Zero means unknown and nothing gets gated
+	port     io.ReadWriteCloser // the serial port, or whatever stream was given to Open. Never changes, see closed
+	closed   atomic.Bool        // set by Close, after which port isn't read or written any more
+	handle   windows.Handle     // InvalidHandle unless port is a COM port
+	dcb      windows.DCB
+	trace    atomic.Pointer[Trace] // see SetTrace
+
+	mu          sync.Mutex // guards everything below
+	flushWindow time.Duration
+	flushTimer  *time.Timer
+	flushErr    error
+	pending     []byte // commands waiting for the auto flush window
+
+	coalesceTick  time.Duration
+	coalesceTimer *time.Timer
+	moveX, moveY  int  // displacement the coalescer hasn't sent yet
+	movePending   bool // moveX/moveY hold at least one MoveMouse call
+	coalesceStats CoalesceStats
+
+	splitMoves bool       // see SetSplitMoves
+	locked     LockTarget // what Lock/WithLock locked and Close has to unlock
+
+	typingDelay     time.Duration   // see SetTypingDelay
+	unicodeFallback UnicodeFallback // see SetUnicodeFallback
+
+	checked    bool          // see SetChecked
+	ackTimeout time.Duration // see SetAck
+	ackStats   AckStats
+	ackBuf     []byte
+	rxScratch  [256]byte // read buffer for nextReply when the background reader isn't running
+
+	flowMu     sync.Mutex // guards the flow control fields, taken by every Write
+	flowPolicy FlowPolicy // see SetFlowControl
+	flowBuffer int
+	busyUntil  time.Time // when the link will have carried everything written so far
+	flowStats  FlowStats
+
+	rxMu    sync.Mutex    // guards everything below, never held while waiting on the port
+	rxBuf   []byte        // reply bytes read from the port that haven't been handed out yet
+	rxReady chan struct{} // poked whenever rxBuf grows
+	reading bool          // the background reader is running
+	stopRd  bool          // asks the reader to exit after its current read
+	readErr error         // why the reader last stopped, see StreamErr
+	rdDone  chan struct{} // closed once the reader has exited
+	demux   demux
+	subs    map[eventKind][]chan deviceEvent
+
+	streamMu sync.Mutex        // held while turning firmware streams on and off
+	streams  map[eventKind]int // live subscriptions per stream, see streamAcquire
+
+	cmdBuf  []byte // scratch space commands are encoded into, reused so the hot path doesn't allocate
+	moveBuf []byte // same for the coalescer, which encodes while cmdBuf may be in use
+}
+
+// 🐱 Baud rates tried (in order) when auto-detecting. 115200 is the MAKCU power-on default and 4m is what ChangeBaudRate switches to.
+var ProbeBaudRates = []uint32{115200, 4000000}
+
+// Make a connection to the COM port where our MAKCU was found.
+// Passing a baudRate of 0 auto-detects the rate the MAKCU is currently speaking (see ConnectAuto).
+func Connect(portName string, baudRate uint32) (*MakcuHandle, error) {
+	if baudRate == 0 {
+		return ConnectAuto(portName)
+	}
+
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	openPort := kernel32.NewProc("CreateFileW")
+
+	if !strings.HasPrefix(portName, `\\.\`) {
+		portName = `\\.\` + portName
+	}
+
+	path, err := windows.UTF16PtrFromString(portName)
+	if err != nil {
+		return nil, fmt.Errorf("Connect: failed to convert port name to UTF16: %w", err)
+	}
+
+	handle, _, err := openPort.Call(uintptr(unsafe.Pointer(path)), syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, 0, 3, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if handle == uintptr(syscall.InvalidHandle) {
+		if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+			return nil, fmt.Errorf("Connect: %w (no port %s)", ErrDeviceNotFound, portName)
+		}
+		return nil, fmt.Errorf("Connect: failed to open port: %w", err)
+	}
+
+	portHandle := windows.Handle(handle)
+	// set the settings for the serial communications
+	dcbOpts := &windows.DCB{}
+	dcbOpts.DCBlength = uint32(unsafe.Sizeof(*dcbOpts))
+	dcbOpts.Flags = 0
+	dcbOpts.ByteSize = 8
+	dcbOpts.Parity = 0
+	dcbOpts.StopBits = 1
+
+	dcbOpts.Flags |= 0x00000400
+	dcbOpts.Flags |= 0x00000800
+
+	m := &MakcuHandle{
+		Port:    strings.TrimPrefix(portName, `\\.\`),
+		port:    &serialPort{handle: portHandle},
+		handle:  portHandle,
+		dcb:     *dcbOpts,
+		rxReady: make(chan struct{}, 1),
+	}
+
+	if err := m.setBaudRate(baudRate); err != nil {
+		_ = windows.CloseHandle(portHandle)
+		return nil, fmt.Errorf("Connect: %w", err)
+	}
+
+	err = SetTimeouts(portHandle)
+	if err != nil {
+		_ = windows.CloseHandle(portHandle)
+		return nil, fmt.Errorf("Connect: failed to set timeouts: %w", err)
+	}
+
+	DebugPrint("Successfully Connected to MAKCU! {Port %s | Baud Rate %d}\n", m.Port, baudRate)
+
+	return m, nil
+}
+
+// 🐱🐱🐱 Cat connect! 🐱🐱🐱
+
+// Opens the COM port and probes each candidate baud rate with km.version() until the MAKCU answers.
+// The returned handle is left at the detected rate, which is reported in its BaudRate field.
+// With no candidates given, ProbeBaudRates is used.
+func ConnectAuto(portName string, candidates ...uint32) (*MakcuHandle, error) {
+	if len(candidates) == 0 {
+		candidates = ProbeBaudRates
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("ConnectAuto: no candidate baud rates to probe")
+	}
+
+	for _, rate := range candidates {
+		if rate == 0 {
+			return nil, fmt.Errorf("ConnectAuto: 0 is not a valid candidate baud rate")
+		}
+	}
+
+	m, err := Connect(portName, candidates[0])
+	if err != nil {
+		return nil, fmt.Errorf("ConnectAuto: %w", err)
+	}
+
+	for i, rate := range candidates {
+		if i > 0 {
+			if err := m.setBaudRate(rate); err != nil {
+				DebugPrint("Skipping baud rate %d: %v\n", rate, err)
+				continue
+			}
+		}
+
+		reply, err := m.probeVersion()
+		if err != nil {
+			DebugPrint("No MAKCU at %d baud: %v\n", rate, err)
+			continue
+		}
+
+		DebugPrint("Detected MAKCU at %d baud (%q)\n", rate, reply)
+
+		if v, err := ParseFirmwareVersion(reply); err == nil {
+			m.Firmware = v
+		}
+
+		return m, nil
+	}
+
+	_ = m.Close()
+	return nil, fmt.Errorf("ConnectAuto: %w (no answer at any of %v baud)", ErrDeviceNotFound, candidates)
+}
+
+// 🐱 Applies a new baud rate to the already open port
+func (m *MakcuHandle) setBaudRate(baudRate uint32) error {
+	if m.handle == windows.InvalidHandle {
+		return fmt.Errorf("can't set baud rate %d: not a COM port", baudRate)
+	}
+
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	setCommState := kernel32.NewProc("SetCommState")
+
+	dcb := m.dcb
+	dcb.BaudRate = baudRate
+
+	ret, _, err := setCommState.Call(uintptr(m.handle), uintptr(unsafe.Pointer(&dcb)))
+	if ret == 0 {
+		return fmt.Errorf("failed to set communication state (baud %d): %w", baudRate, err)
+	}
+
+	m.dcb = dcb
+	m.BaudRate = baudRate
+
+	return nil
+}
+
+// 🐱 Sends km.version() and waits for a reply containing "MAKCU", returning the reply
+func (m *MakcuHandle) probeVersion() (string, error) {
+	// drop whatever garbage a previous wrong-rate probe left in the buffers
+	m.purge()
+
+	if _, err := m.Write([]byte("km.version()\r")); err != nil {
+		return "", err
+	}
+
+	var reply []byte
+	ReadBuf := make([]byte, 64)
+	deadline := time.Now().Add(probeTimeout)
+	for time.Now().Before(deadline) {
+		n, err := m.Read(ReadBuf)
+		if err != nil {
+			return "", err
+		}
+
+		reply = append(reply, ReadBuf[:n]...)
+		if strings.Contains(string(reply), "MAKCU") {
+			return string(reply), nil
+		}
+	}
+
+	if len(reply) == 0 {
+		return "", fmt.Errorf("km.version(): %w", ErrTimeout)
+	}
+
+	return "", &DeviceError{Op: "probeVersion", Command: "km.version()", Reply: string(reply)}
+}
+
+// 🐱 How long to wait for a km.version() reply at each candidate baud rate
+const probeTimeout = 1 * time.Second
+
+// 🐱 Throws away everything received but not read yet
+func (m *MakcuHandle) purge() {
+	m.rxMu.Lock()
+	m.rxBuf = m.rxBuf[:0]
+	m.rxMu.Unlock()
+
+	if m.handle != windows.InvalidHandle {
+		_ = windows.PurgeComm(m.handle, windows.PURGE_RXCLEAR|windows.PURGE_TXCLEAR)
+	}
+}
+
+// 🐱🐱🐱 Cat auto baud! 🐱🐱🐱
+
+// Wraps any stream that speaks the MAKCU protocol (a network bridge, a makcufake.Device in tests, ...) in a handle.
+// Things that only make sense for a COM port, like changing the baud rate, fail on it. Reads don't need a timeout:
+// Close closes rw, which has to make a blocked Read return.
+func Open(rw io.ReadWriteCloser) *MakcuHandle {
+	return &MakcuHandle{
+		port:    rw,
+		handle:  windows.InvalidHandle,
+		rxReady: make(chan struct{}, 1),
+	}
+}
+
+// 🐱 The COM port, read and written with plain (non overlapped) ReadFile/WriteFile
+type serialPort struct {
+	handle windows.Handle
+}
+
+// windows.WriteFile/ReadFile instead of a LazyProc.Call, which forces the byte count onto the heap on every call
+func (p *serialPort) Write(b []byte) (int, error) {
+	var n uint32
+	err := windows.WriteFile(p.handle, b, &n, nil)
+	return int(n), err
+}
+
+func (p *serialPort) Read(b []byte) (int, error) {
+	var n uint32
+	err := windows.ReadFile(p.handle, b, &n, nil)
+	return int(n), err
+}
+
+func (p *serialPort) Close() error {
+	return windows.CloseHandle(p.handle)
+}
+
+// 🐱🐱🐱 Cat ports! 🐱🐱🐱
+
+// Close the connection to the MAKCU
+func (m *MakcuHandle) Close() error {
+	if m == nil {
+		return fmt.Errorf("Close: %w", ErrNotConnected)
+	}
+
+	if m.closed.Load() || m.port == nil {
+		return fmt.Errorf("Close: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	if err := m.flushAllLocked(); err != nil {
+		ErrorPrint("Close: failed to flush pending commands: %v", err)
+	}
+	m.flushWindow = 0
+	m.coalesceTick = 0
+	if m.locked != 0 {
+		if err := m.setLockLocked("Close", m.locked, false); err != nil {
+			ErrorPrint("Close: failed to unlock %s: %v", m.locked, err)
+		}
+	}
+	m.mu.Unlock()
+
+	m.stopStreams()
+
+	if m.closed.Swap(true) {
+		// lost a race with another Close
+		return fmt.Errorf("Close: %w", ErrNotConnected)
+	}
+
+	// a COM port's reader has to be gone before the handle is closed, or its next ReadFile could hit a reused
+	// handle. Any other port (a net.Conn, a pipe, ...) may block in Read for good, so it is closed first and the
+	// failing Read ends the reader.
+	var err error
+	if m.handle != windows.InvalidHandle {
+		m.stopReader()
+		err = m.port.Close()
+	} else {
+		err = m.port.Close()
+		m.stopReader()
+	}
+	m.handle = windows.InvalidHandle
+	if err != nil {
+		return fmt.Errorf("Close: failed to close handle: %w", err)
+	}
+
+	return nil
+}
+
+// 🐱🐱🐱 Cat close! 🐱🐱🐱
+
+// Sends the bytes needed to change the Baud Rate of the MAKCU to 4m and then returns a new Connection object with the new baud rate
+// Note: This is NOT a permanent change and will reset back to the default 115200 baud rate after the MAKCU powers off and then back on again.
+func ChangeBaudRate(m *MakcuHandle) (*MakcuHandle, error) {
+	if m == nil {
+		return nil, fmt.Errorf("ChangeBaudRate: %w", ErrNotConnected)
+	}
+
+	locked, err := m.switchBaud(4000000)
+	if err != nil {
+		return nil, fmt.Errorf("ChangeBaudRate: %w", err)
+	}
+
+	NewConn, err := Connect(m.Port, 4000000)
+	if err != nil {
+		return nil, fmt.Errorf("ChangeBaudRate: connect error: %w", err)
+	}
+
+	// the MAKCU keeps its locks across the switch, so the new handle takes over unlocking them
+	NewConn.locked = locked
+
+	time.Sleep(1 * time.Second)
+
+	_, err = NewConn.Write([]byte("km.version()\r"))
+	if err != nil {
+		_ = NewConn.Close()
+		return nil, fmt.Errorf("ChangeBaudRate: write error after reconnect: %w", err)
+	}
+
+	ReadBuf := make([]byte, 32)
+	n, err := NewConn.Read(ReadBuf)
+	if err != nil {
+		_ = NewConn.Close()
+		return nil, fmt.Errorf("ChangeBaudRate: read error after reconnect: %w", err)
+	}
+
+	if !strings.Contains(string(ReadBuf[:n]), "MAKCU") {
+		_ = NewConn.Close()
+		if n == 0 {
+			return nil, fmt.Errorf("ChangeBaudRate: no reply after reconnect: %w", ErrTimeout)
+		}
+		return nil, &DeviceError{Op: "ChangeBaudRate", Command: "km.version()", Reply: string(ReadBuf[:n])}
+	}
+
+	if v, err := ParseFirmwareVersion(string(ReadBuf[:n])); err == nil {
+		NewConn.Firmware = v
+	}
+
+	time.Sleep(1 * time.Second)
+
+	DebugPrint("Successfully Changed Baud Rate To %d!\n", 4000000)
+
+	return NewConn, nil
+}
+
+// 🐱 Sends the set baud rate frame and closes the handle. Everything else the handle still has to send (pending
+// commands, turning streams off) goes out first, at the rate the MAKCU is still listening at. Close can't unlock
+// anything afterwards, so the locks are returned for the new handle instead.
+func (m *MakcuHandle) switchBaud(baudRate uint32) (LockTarget, error) {
+	m.mu.Lock()
+	if err := m.flushAllLocked(); err != nil {
+		ErrorPrint("ChangeBaudRate: failed to flush pending commands: %v", err)
+	}
+	m.flushWindow = 0
+	m.coalesceTick = 0
+	locked := m.locked
+	m.locked = 0
+	m.mu.Unlock()
+
+	m.stopStreams()
+
+	frame := setBaudFrame(baudRate) // DE AD 05 00 A5 00 09 3D 00 for 4m
+	n, err := m.Write(frame)
+	if err == nil && n != len(frame) {
+		err = fmt.Errorf("wrong number of bytes written (got %d, want %d)", n, len(frame))
+	}
+
+	if err != nil {
+		// the MAKCU is most likely still at the old rate, so let Close unlock there
+		m.mu.Lock()
+		m.locked = locked
+		m.mu.Unlock()
+
+		// Always try to close the handle on error
+		_ = m.Close()
+		return 0, fmt.Errorf("write error: %w", err)
+	}
+
+	if err := m.Close(); err != nil {
+		ErrorPrint("ChangeBaudRate: failed to close old connection: %v", err)
+		// Continue, but log the error
+	}
+
+	return locked, nil
+}
+
+// 🐱🐱🐱 Cat baud rate! 🐱🐱🐱
+
+// Sends the given bytes to the MAKCU and returns the number of bytes written.
+func (m *MakcuHandle) Write(data []byte) (int, error) {
+	if m == nil {
+		return -1, fmt.Errorf("Write: %w", ErrNotConnected)
+	}
+
+	if m.closed.Load() || m.port == nil {
+		return -1, fmt.Errorf("Write: %w", ErrNotConnected)
+	}
+
+	// checked first so the hot path doesn't box data into an interface when nobody is listening
+	if Debug {
+		DebugPrint("Sending %s\r\n", data[:])
+	}
+
+	if err := m.admit(len(data)); err != nil {
+		return -1, fmt.Errorf("Write: %w", err)
+	}
+
+	bytesWritten, err := m.port.Write(data)
+	if err != nil {
+		return -1, fmt.Errorf("Write: error writing to port: %w", err)
+	}
+
+	if t := m.trace.Load(); t != nil {
+		t.record(TX, data[:bytesWritten])
+	}
+
+	return bytesWritten, nil
+}
+
+// 🐱🐱🐱 Cat write! 🐱🐱🐱
+
+// Reads data from the MAKCU and saves it to a given buffer then returns the number of bytes read.
+// Once something has started the background reader (e.g. a button or motion subscription), Read only returns
+// command replies. The streamed events are split out and delivered to their subscribers instead.
+func (m *MakcuHandle) Read(buffer []byte) (int, error) {
+	if m == nil || m.closed.Load() {
+		return -1, fmt.Errorf("Read: %w", ErrNotConnected)
+	}
+
+	m.rxMu.Lock()
+	queued, reading := len(m.rxBuf), m.reading
+	m.rxMu.Unlock()
+
+	if queued > 0 || reading {
+		return m.readReply(buffer, ReplyTimeout)
+	}
+
+	return m.readPort(buffer)
+}
+
+// 🐱 Reads straight from the port
+func (m *MakcuHandle) readPort(buffer []byte) (int, error) {
+	if m.closed.Load() || m.port == nil {
+		return -1, fmt.Errorf("Read: %w", ErrNotConnected)
+	}
+
+	bytesRead, err := m.port.Read(buffer)
+	if err != nil {
+		return -1, fmt.Errorf("Read: error reading from port: %w", err)
+	}
+
+	if t := m.trace.Load(); bytesRead > 0 && t != nil {
+		t.record(RX, buffer[:bytesRead])
+	}
+
+	return bytesRead, nil
+}
+
+// 🐱🐱🐱 Cat read! 🐱🐱🐱
+
+// 🐱 Mouse left down
+func (m *MakcuHandle) LeftDown() error {
+	return m.Press(ButtonLeft)
+}
+
+// 🐱🐱🐱 Cat left down! 🐱🐱🐱
+
+// 🐱 Mouse left up
+func (m *MakcuHandle) LeftUp() error {
+	return m.Release(ButtonLeft)
+}
+
+// 🐱🐱🐱 Cat left up! 🐱🐱🐱
+
+// 🐱 Mouse left click
+func (m *MakcuHandle) LeftClick() error {
+	return m.Tap(ButtonLeft, 0)
+}
+
+// 🐱🐱🐱 Cat left click! 🐱🐱🐱
+
+// 🐱 Mouse right down
+func (m *MakcuHandle) RightDown() error {
+	return m.Press(ButtonRight)
+}
+
+// 🐱🐱🐱 Cat right down! 🐱🐱🐱
+
+// 🐱 Mouse right up
+func (m *MakcuHandle) RightUp() error {
+	return m.Release(ButtonRight)
+}
+
+// 🐱🐱🐱 Cat right up! 🐱🐱🐱
+
+// 🐱 Mouse right click
+func (m *MakcuHandle) RightClick() error {
+	return m.Tap(ButtonRight, 0)
+}
+
+// 🐱🐱🐱 Cat right click! 🐱🐱🐱
+
+// 🐱 Mouse middle down
+func (m *MakcuHandle) MiddleDown() error {
+	return m.Press(ButtonMiddle)
+}
+
+// 🐱🐱🐱 Cat middle down! 🐱🐱🐱
+
+// 🐱 Mouse middle up
+func (m *MakcuHandle) MiddleUp() error {
+	return m.Release(ButtonMiddle)
+}
+
+// 🐱🐱🐱 Cat middle up! 🐱🐱🐱
+
+// 🐱 Mouse middle click
+func (m *MakcuHandle) MiddleClick() error {
+	return m.Tap(ButtonMiddle, 0)
+}
+
+// 🐱🐱🐱 Cat middle click! 🐱🐱🐱
+
+// 🐱 Mouse side 1 down (mouse 4, usually back)
+func (m *MakcuHandle) Side1Down() error {
+	return m.Press(ButtonSide1)
+}
+
+// 🐱🐱🐱 Cat side1 down! 🐱🐱🐱
+
+// 🐱 Mouse side 1 up (mouse 4, usually back)
+func (m *MakcuHandle) Side1Up() error {
+	return m.Release(ButtonSide1)
+}
+
+// 🐱🐱🐱 Cat side1 up! 🐱🐱🐱
+
+// 🐱 Mouse side 1 click (mouse 4, usually back)
+func (m *MakcuHandle) Side1Click() error {
+	return m.Tap(ButtonSide1, 0)
+}
+
+// 🐱🐱🐱 Cat side1 click! 🐱🐱🐱
+
+// 🐱 Mouse side 2 down (mouse 5, usually forward)
+func (m *MakcuHandle) Side2Down() error {
+	return m.Press(ButtonSide2)
+}
+
+// 🐱🐱🐱 Cat side2 down! 🐱🐱🐱
+
+// 🐱 Mouse side 2 up (mouse 5, usually forward)
+func (m *MakcuHandle) Side2Up() error {
+	return m.Release(ButtonSide2)
+}
+
+// 🐱🐱🐱 Cat side2 up! 🐱🐱🐱
+
+// 🐱 Mouse side 2 click (mouse 5, usually forward)
+func (m *MakcuHandle) Side2Click() error {
+	return m.Tap(ButtonSide2, 0)
+}
+
+// 🐱🐱🐱 Cat side2 click! 🐱🐱🐱
+
+// 🐱 Mouse button constants, the same numbers as the Button values
+const (
+	MOUSE_BUTTON_LEFT   = 1
+	MOUSE_BUTTON_RIGHT  = 2
+	MOUSE_BUTTON_MIDDLE = 3
+	MOUSE_BUTTON_SIDE1  = 4 // needs CapSideButtons
+	MOUSE_BUTTON_SIDE2  = 5 // needs CapSideButtons
+)
+
+// 🐱 Clicks a mouse button, holding it down for delay
+func (m *MakcuHandle) Click(i int, delay time.Duration) error {
+	if m == nil {
+		return fmt.Errorf("Click: %w", ErrNotConnected)
+	}
+
+	// checked as an int, converting first would wrap 257 or -255 around to a valid button
+	if i < MOUSE_BUTTON_LEFT || i > MOUSE_BUTTON_SIDE2 {
+		return fmt.Errorf("Click: %w: %d", ErrInvalidButton, i)
+	}
+
+	return m.Tap(Button(i), delay)
+}
+
+func (m *MakcuHandle) ClickMouse() error {
+	if m == nil {
+		return fmt.Errorf("ClickMouse: %w", ErrNotConnected)
+	}
+
+	return m.Click(MOUSE_BUTTON_LEFT, 0)
+}
+
+// 🐱🐱🐱 Cat click! 🐱🐱🐱
+
+// 🐱 Scrolls the mouse
+func (m *MakcuHandle) ScrollMouse(amount int) error {
+	if m == nil {
+		return fmt.Errorf("ScrollMouse: %w", ErrNotConnected)
+	}
+
+	err := m.KmWheel(amount)
+	if err != nil {
+		DebugPrint("Failed to scroll mouse: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// 🐱🐱🐱 Cat scroll! 🐱🐱🐱
+
+// 🐱 Moves the mouse
+func (m *MakcuHandle) MoveMouse(x, y int) error {
+	if m == nil {
+		return fmt.Errorf("MoveMouse: %w", ErrNotConnected)
+	}
+
+	err := m.move(x, y)
+	if err != nil {
+		DebugPrint("Failed to move mouse: Write Error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// 🐱🐱🐱 Cat move! 🐱🐱🐱
+
+// use a curve with the built in curve functionality from MAKCU. On firmware without CapCurveMove the curve is emulated host side (see emulateCurve).
+// "It is common sense that the higher the number of the third parameter, the smoother the curve will be fitted" - from MAKCU/km box docs
+func (m *MakcuHandle) MoveMouseWithCurve(x, y int, params ...int) error {
+	if m == nil {
+		return fmt.Errorf("MoveMouseWithCurve: %w", ErrNotConnected)
+	}
+
+	switch len(params) {
+	case 0, 1, 3:
+	default:
+		DebugPrint("Invalid number of parameters")
+		return fmt.Errorf("MoveMouseWithCurve: invalid number of parameters %d (want 0, 1 or 3)", len(params))
+	}
+
+	var err error
+	switch {
+	case len(params) == 0:
+		err = m.move(x, y)
+	case !m.Supports(CapCurveMove):
+		// older firmware ignores the curved forms, so walk the same path from here with plain km.move steps
+		err = m.emulateCurve(x, y, params...)
+	default:
+		err = m.curve(x, y, params...)
+	}
+
+	if err != nil {
+		DebugPrint("Failed to move mouse with curve: Write Error: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+// Code generated by cmdgen from commands.json. DO NOT EDIT.
+
+package makcufake
+
+// signatures lists every km.* form the firmware accepts
+var signatures = []signature{
+	{fn: "left", name: "Left", args: []arg{{"state", 0, 1}}},
+	{fn: "right", name: "Right", args: []arg{{"state", 0, 1}}},
+	{fn: "middle", name: "Middle", args: []arg{{"state", 0, 1}}},
+	{fn: "side1", name: "Side1", args: []arg{{"state", 0, 1}}},
+	{fn: "side2", name: "Side2", args: []arg{{"state", 0, 1}}},
+	{fn: "left", name: "LeftState", query: true, args: []arg{}},
+	{fn: "right", name: "RightState", query: true, args: []arg{}},
+	{fn: "middle", name: "MiddleState", query: true, args: []arg{}},
+	{fn: "side1", name: "Side1State", query: true, args: []arg{}},
+	{fn: "side2", name: "Side2State", query: true, args: []arg{}},
+	{fn: "buttons", name: "ButtonStream", args: []arg{{"enable", 0, 1}}},
+	{fn: "catch_xy", name: "MotionStream", args: []arg{{"enable", 0, 1}}},
+	{fn: "lock_mx", name: "LockX", args: []arg{{"locked", 0, 1}}},
+	{fn: "lock_my", name: "LockY", args: []arg{{"locked", 0, 1}}},
+	{fn: "lock_ml", name: "LockLeft", args: []arg{{"locked", 0, 1}}},
+	{fn: "lock_mr", name: "LockRight", args: []arg{{"locked", 0, 1}}},
+	{fn: "lock_mm", name: "LockMiddle", args: []arg{{"locked", 0, 1}}},
+	{fn: "lock_ms1", name: "LockSide1", args: []arg{{"locked", 0, 1}}},
+	{fn: "lock_ms2", name: "LockSide2", args: []arg{{"locked", 0, 1}}},
+	{fn: "lock_mw", name: "LockWheel", args: []arg{{"locked", 0, 1}}},
+	{fn: "down", name: "KeyDown", args: []arg{{"key", 0, 255}}},
+	{fn: "up", name: "KeyUp", args: []arg{{"key", 0, 255}}},
+	{fn: "wheel", name: "Wheel", args: []arg{{"amount", -127, 127}}},
+	{fn: "move", name: "Move", args: []arg{{"x", -32767, 32767}, {"y", -32767, 32767}}},
+	{fn: "move", name: "MoveSegments", args: []arg{{"x", -32767, 32767}, {"y", -32767, 32767}, {"segments", 1, 32767}}},
+	{fn: "move", name: "MoveBezier", args: []arg{{"x", -32767, 32767}, {"y", -32767, 32767}, {"segments", 1, 32767}, {"cx", -32767, 32767}, {"cy", -32767, 32767}}},
+}
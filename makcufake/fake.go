@@ -0,0 +1,395 @@
+// Package makcufake is an in-memory MAKCU for exercising code that drives one without the hardware.
+//
+//	dev := makcufake.New()
+//	conn := makcu.Open(dev)
+//	conn.LeftClick()
+//	fmt.Println(dev.Calls()) // [Left(1) Left(0)]
+//
+// It speaks the same text protocol as the firmware: every command is echoed back followed by the ">>> " prompt,
+// and commands that don't match a signature from commands.json get an error line like the real device prints.
+package makcufake
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 🐱 Prompt the firmware prints after every reply
+const prompt = ">>> "
+
+// 🐱 One km.* form, generated from commands.json
+type signature struct {
+	fn    string
+	name  string
+	query bool // answers with a number instead of doing something
+	args  []arg
+}
+
+type arg struct {
+	name     string
+	min, max int
+}
+
+// 🐱 A command the fake accepted
+type Call struct {
+	Name string // Go name from commands.json, e.g. "MoveSegments"
+	Fn   string // firmware function, e.g. "move"
+	Args []int
+}
+
+func (c Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		args[i] = strconv.Itoa(a)
+	}
+
+	return c.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// 🐱 A binary frame (0xDE 0xAD ...) the fake received
+type Frame struct {
+	Cmd     byte
+	Payload []byte
+}
+
+// 🐱 Fake MAKCU. It implements io.ReadWriteCloser, so it can be handed to makcu.Open.
+type Device struct {
+	// Version is what km.version() answers with.
+	Version string
+
+	// ReadTimeout is how long Read waits for output before returning 0 bytes, like a serial port read timeout.
+	ReadTimeout time.Duration
+
+	mu       sync.Mutex
+	in       []byte // bytes written but not yet a complete command
+	out      []byte // bytes waiting to be read
+	ready    chan struct{}
+	calls    []Call
+	rejected []string
+	frames   []Frame
+	values   map[string]int // what km.<fn>() answers, kept up to date by km.<fn>(state)
+	drop     int            // replies still to swallow, see DropReplies
+	closed   bool
+}
+
+// 🐱 Creates a fake running firmware 3.5.0, which has every capability
+func New() *Device {
+	return &Device{
+		Version:     "km.MAKCU v3.5.0",
+		ReadTimeout: 10 * time.Millisecond,
+		ready:       make(chan struct{}, 1),
+		values:      map[string]int{},
+	}
+}
+
+var errClosed = errors.New("makcufake: device closed")
+
+// 🐱 Takes commands and binary frames, queueing the replies
+func (d *Device) Write(b []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return 0, errClosed
+	}
+
+	d.in = append(d.in, b...)
+	for len(d.in) > 0 {
+		if d.in[0] == 0xDE {
+			if !d.takeFrame() {
+				break
+			}
+			continue
+		}
+
+		i := bytes.IndexByte(d.in, '\r')
+		if i < 0 {
+			break
+		}
+
+		line := strings.TrimSpace(string(d.in[:i]))
+		d.in = d.in[i+1:]
+		if line != "" {
+			d.exec(line)
+		}
+	}
+
+	d.wake()
+	return len(b), nil
+}
+
+// 🐱 Consumes the binary frame at the start of d.in. Returns false if it hasn't fully arrived yet.
+func (d *Device) takeFrame() bool {
+	if len(d.in) < 4 {
+		return false
+	}
+
+	if d.in[1] != 0xAD {
+		// not a frame after all, drop the stray byte like the firmware does
+		d.in = d.in[1:]
+		return true
+	}
+
+	size := int(d.in[2]) | int(d.in[3])<<8
+	if len(d.in) < 4+size {
+		return false
+	}
+
+	if size > 0 {
+		d.frames = append(d.frames, Frame{Cmd: d.in[4], Payload: append([]byte(nil), d.in[5:4+size]...)})
+	}
+
+	d.in = d.in[4+size:]
+	return true
+}
+
+// 🐱 Runs one command line and queues its reply
+func (d *Device) exec(line string) {
+	if d.drop > 0 {
+		// the command still runs, only its reply is lost
+		d.drop--
+		defer func(n int) { d.out = d.out[:n] }(len(d.out))
+	}
+
+	d.out = append(d.out, line...)
+	d.out = append(d.out, "\r\n"...)
+
+	fn, args, err := parse(line)
+	switch {
+	case err != nil:
+		d.reject(line, err.Error())
+	case fn == "version" && len(args) == 0:
+		d.out = append(d.out, d.Version...)
+		d.out = append(d.out, "\r\n"...)
+	default:
+		call, query, err := match(fn, args)
+		if err != nil {
+			d.reject(line, err.Error())
+			break
+		}
+		d.calls = append(d.calls, call)
+
+		if query {
+			d.out = strconv.AppendInt(d.out, int64(d.values[fn]), 10)
+			d.out = append(d.out, "\r\n"...)
+		} else if len(args) == 1 {
+			d.values[fn] = args[0]
+		}
+	}
+
+	d.out = append(d.out, prompt...)
+}
+
+func (d *Device) reject(line, reason string) {
+	d.rejected = append(d.rejected, line)
+	d.out = append(d.out, "Error: "...)
+	d.out = append(d.out, reason...)
+	d.out = append(d.out, "\r\n"...)
+}
+
+// 🐱 Finds the signature fn(args) belongs to and checks the argument ranges
+func match(fn string, args []int) (call Call, query bool, err error) {
+	known := false
+	for _, sig := range signatures {
+		if sig.fn != fn {
+			continue
+		}
+		known = true
+
+		if len(sig.args) != len(args) {
+			continue
+		}
+
+		for i, a := range sig.args {
+			if args[i] < a.min || args[i] > a.max {
+				return Call{}, false, fmt.Errorf("%s out of range (%d not in %d..%d)", a.name, args[i], a.min, a.max)
+			}
+		}
+
+		return Call{Name: sig.name, Fn: fn, Args: args}, sig.query, nil
+	}
+
+	if !known {
+		return Call{}, false, fmt.Errorf("unknown function km.%s", fn)
+	}
+
+	return Call{}, false, fmt.Errorf("invalid number of arguments to km.%s", fn)
+}
+
+// 🐱 "km.move(1, 2)" -> "move", [1 2]
+func parse(line string) (string, []int, error) {
+	rest, ok := strings.CutPrefix(line, "km.")
+	if !ok {
+		return "", nil, fmt.Errorf("invalid syntax")
+	}
+
+	open := strings.IndexByte(rest, '(')
+	if open <= 0 || !strings.HasSuffix(rest, ")") {
+		return "", nil, fmt.Errorf("invalid syntax")
+	}
+
+	fn, inner := rest[:open], strings.TrimSpace(rest[open+1:len(rest)-1])
+	if inner == "" {
+		return fn, nil, nil
+	}
+
+	var args []int
+	for _, a := range strings.Split(inner, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(a))
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid argument %q", strings.TrimSpace(a))
+		}
+		args = append(args, v)
+	}
+
+	return fn, args, nil
+}
+
+// 🐱 Hands out queued output, waiting up to ReadTimeout for some to show up
+func (d *Device) Read(b []byte) (int, error) {
+	timer := time.NewTimer(d.ReadTimeout)
+	defer timer.Stop()
+
+	for {
+		d.mu.Lock()
+		if d.closed {
+			d.mu.Unlock()
+			return 0, errClosed
+		}
+
+		if len(d.out) > 0 {
+			n := copy(b, d.out)
+			d.out = d.out[n:]
+			d.mu.Unlock()
+			return n, nil
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-d.ready:
+		case <-timer.C:
+			return 0, nil
+		}
+	}
+}
+
+// 🐱 Closes the device. Further reads and writes fail.
+func (d *Device) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return errClosed
+	}
+
+	d.closed = true
+	d.wake()
+	return nil
+}
+
+// 🐱 Queues raw bytes for the host to read, e.g. a button stream packet
+func (d *Device) Inject(b []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.out = append(d.out, b...)
+	d.wake()
+}
+
+func (d *Device) wake() {
+	select {
+	case d.ready <- struct{}{}:
+	default:
+	}
+}
+
+// 🐱 Sets what km.<fn>() answers, e.g. SetValue("left", 1) makes the left button look held down by the user.
+// Commands setting the same state (km.left(0)) overwrite it like on the real device.
+func (d *Device) SetValue(fn string, v int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.values[fn] = v
+}
+
+// 🐱 Swallows the whole reply (echo, output and prompt) to the next n commands, as if it got lost on the line.
+// The commands still run.
+func (d *Device) DropReplies(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.drop = n
+}
+
+// 🐱 Pretends the user is holding the buttons in mask (bit 0 left .. bit 4 side2) on the physical mouse. The
+// button queries answer accordingly, and if the host turned on km.buttons streaming the change is sent to it.
+func (d *Device) PhysicalButtons(mask byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, fn := range []string{"left", "right", "middle", "side1", "side2"} {
+		d.values[fn] = int(mask>>i) & 1
+	}
+
+	if d.values["buttons"] == 1 {
+		d.out = append(d.out, "km."...)
+		d.out = append(d.out, mask&0x1F)
+		d.wake()
+	}
+}
+
+// 🐱 Pretends the user moved the physical mouse. Sent to the host as a motion frame if it turned on km.catch_xy.
+func (d *Device) PhysicalMotion(dx, dy, wheel int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.values["catch_xy"] != 1 {
+		return
+	}
+
+	d.out = append(d.out, 0xDE, 0xAD, 6, 0, 0xB0)
+	d.out = binary.LittleEndian.AppendUint16(d.out, uint16(int16(dx)))
+	d.out = binary.LittleEndian.AppendUint16(d.out, uint16(int16(dy)))
+	d.out = append(d.out, byte(int8(wheel)))
+	d.wake()
+}
+
+// 🐱 Returns every command accepted so far, in order
+func (d *Device) Calls() []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]Call(nil), d.calls...)
+}
+
+// 🐱 Returns every command line the fake answered with an error
+func (d *Device) Rejected() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]string(nil), d.rejected...)
+}
+
+// 🐱 Returns every binary frame received so far
+func (d *Device) Frames() []Frame {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]Frame(nil), d.frames...)
+}
+
+// 🐱 Forgets the recorded calls, rejections and frames
+func (d *Device) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.calls, d.rejected, d.frames = nil, nil, nil
+}
+
+// 🐱🐱🐱 Cat fake! 🐱🐱🐱
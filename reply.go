@@ -0,0 +1,170 @@
+package makcu
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 🐱 How long checked mode and queries wait for the MAKCU to answer each command
+var ReplyTimeout = 500 * time.Millisecond
+
+// 🐱 The MAKCU echoes every command and then prints this prompt once it is done with it
+const replyPrompt = ">>> "
+
+// 🐱 Bits of text the firmware only ever prints when it rejected a command
+var firmwareErrorMarkers = []string{"Traceback", "Error", "error", "Invalid", "invalid", "Unknown", "unknown", "out of range"}
+
+// Turns checked mode on or off. In checked mode every command waits for the MAKCU's reply, and if the firmware
+// rejected it (bad syntax, out of range, unknown function, ...) the method that sent it returns a *DeviceError
+// holding the firmware's message. Commands are never held back by the auto flush window while it is on.
+func (m *MakcuHandle) SetChecked(checked bool) error {
+	if m == nil {
+		return fmt.Errorf("SetChecked: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.flushAllLocked(); err != nil {
+		return fmt.Errorf("SetChecked: %w", err)
+	}
+
+	// anything already waiting belongs to commands nobody is going to check
+	m.purge()
+
+	m.checked = checked
+	return nil
+}
+
+// 🐱🐱🐱 Cat checked mode! 🐱🐱🐱
+
+// 🐱 Looks for a firmware error in the reply to cmd. Returns nil if the command went through.
+func parseReply(cmd, reply string) error {
+	cmd = strings.TrimSpace(cmd)
+
+	for _, line := range strings.FieldsFunc(reply, func(r rune) bool { return r == '\r' || r == '\n' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || line == cmd {
+			continue
+		}
+
+		for _, marker := range firmwareErrorMarkers {
+			if strings.Contains(line, marker) {
+				return &DeviceError{Op: commandName(cmd), Command: cmd, Reply: line}
+			}
+		}
+	}
+
+	return nil
+}
+
+// 🐱 "km.move(1, 2)" -> "km.move"
+func commandName(cmd string) string {
+	if fn, _, err := parseCall(cmd); err == nil {
+		return "km." + fn
+	}
+
+	if i := strings.IndexByte(cmd, '('); i >= 0 {
+		return cmd[:i]
+	}
+
+	return cmd
+}
+
+// 🐱 Reads the replies to every command in a frame that was just written and returns the first firmware error.
+// Replies are matched to commands by their echo, so stale ones (from a raw Write, or answers that came in after
+// an earlier timeout) are skipped instead of being blamed on the wrong command. All replies are read even after
+// an error so the next command doesn't pick up one of them.
+func (m *MakcuHandle) checkReplies(frame []byte) error {
+	deadline := time.Now().Add(ReplyTimeout)
+
+	var first error
+	for _, cmd := range bytes.Split(frame, []byte{'\r'}) {
+		cmd = bytes.TrimSpace(cmd)
+		if len(cmd) == 0 {
+			continue
+		}
+
+		reply, err := m.awaitEcho(string(cmd), deadline)
+		if err != nil {
+			// the stream is out of step now, nothing after this can be trusted
+			return fmt.Errorf("%s: %w", commandName(string(cmd)), err)
+		}
+
+		if err := parseReply(string(cmd), reply); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// 🐱🐱🐱 Cat replies! 🐱🐱🐱
+
+// 🐱 Sends km.<fn>() and returns the number the MAKCU answers with
+func (m *MakcuHandle) query(op, fn string) (int, error) {
+	vals, err := m.queryAll(op, fn)
+	if err != nil {
+		return 0, err
+	}
+
+	return vals[0], nil
+}
+
+// 🐱 Sends km.<fn>() for every fn in a single write and returns the answers in the same order
+func (m *MakcuHandle) queryAll(op string, fns ...string) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.flushAllLocked(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.cmdBuf = m.cmdBuf[:0]
+	for _, fn := range fns {
+		m.cmdBuf = appendCall(m.cmdBuf, fn)
+	}
+
+	if _, err := m.Write(m.cmdBuf); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deadline := time.Now().Add(ReplyTimeout)
+	vals := make([]int, len(fns))
+	for i, fn := range fns {
+		cmd := "km." + fn + "()"
+		reply, err := m.awaitEcho(cmd, deadline)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, cmd, err)
+		}
+
+		v, err := parseIntReply(cmd, reply)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		vals[i] = v
+	}
+
+	return vals, nil
+}
+
+// 🐱 Pulls the number out of a query reply ("km.left()\r\n1\r\n"), or the firmware's error if it printed one
+func parseIntReply(cmd, reply string) (int, error) {
+	if err := parseReply(cmd, reply); err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.FieldsFunc(reply, func(r rune) bool { return r == '\r' || r == '\n' }) {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "km.")
+		if v, err := strconv.Atoi(line); err == nil {
+			return v, nil
+		}
+	}
+
+	return 0, &DeviceError{Op: commandName(cmd), Command: cmd, Reply: reply}
+}
+
+// 🐱🐱🐱 Cat queries! 🐱🐱🐱
@@ -0,0 +1,96 @@
+package makcu
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckedReportsFirmwareError(t *testing.T) {
+	m, _ := openFake(t, "")
+	if err := m.SetChecked(true); err != nil {
+		t.Fatal(err)
+	}
+
+	err := m.Batch().Move(1, 2).Raw("km.bogus(1)").Move(3, 4).Flush()
+	var de *DeviceError
+	if !errors.As(err, &de) || de.Command != "km.bogus(1)" {
+		t.Fatalf("got %v, want a DeviceError for km.bogus(1)", err)
+	}
+
+	if err := m.MoveMouse(1, 2); err != nil {
+		t.Errorf("MoveMouse after the rejected command: %v", err)
+	}
+}
+
+// 🐱 A reply nobody waited for must not be blamed on the next checked command
+func TestCheckedSkipsStaleReplies(t *testing.T) {
+	m, _ := openFake(t, "")
+	if err := m.SetChecked(true); err != nil {
+		t.Fatal(err)
+	}
+
+	// raw writes are never checked, so its error reply is left waiting
+	if _, err := m.Write([]byte("km.bogus(1)\r")); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := m.MoveMouse(1, 2); err != nil {
+			t.Fatalf("MoveMouse #%d: %v", i+1, err)
+		}
+	}
+
+	// and a real error still lands on the command that caused it
+	err := m.Batch().Move(1, 2).Raw("km.bogus(2)").Flush()
+	var de *DeviceError
+	if !errors.As(err, &de) || de.Command != "km.bogus(2)" {
+		t.Fatalf("got %v, want a DeviceError for km.bogus(2)", err)
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	for _, tc := range []struct {
+		cmd, reply string
+		bad        bool
+	}{
+		{"km.move(1, 2)", "km.move(1, 2)\r\n", false},
+		{"km.left()", "km.left()\r\n1\r\n", false},
+		{"km.bogus(1)", "km.bogus(1)\r\nError: unknown function km.bogus\r\n", true},
+		{"km.move(99999, 0)", "km.move(99999, 0)\r\nValueError: x out of range\r\n", true},
+		{"km.move(1)", "km.move(1)\r\nTraceback (most recent call last):\r\n", true},
+	} {
+		err := parseReply(tc.cmd, tc.reply)
+		if (err != nil) != tc.bad {
+			t.Errorf("parseReply(%q, %q) = %v, want error %v", tc.cmd, tc.reply, err, tc.bad)
+		}
+	}
+}
+
+func FuzzParseReply(f *testing.F) {
+	cmds, replies := recordedReplies(f)
+	for i := range cmds {
+		f.Add(cmds[i], replies[i])
+	}
+
+	f.Fuzz(func(t *testing.T, cmd, reply string) {
+		err := parseReply(cmd, reply)
+		if err == nil {
+			return
+		}
+
+		var de *DeviceError
+		if !errors.As(err, &de) {
+			t.Fatalf("parseReply(%q, %q) = %v, not a *DeviceError", cmd, reply, err)
+		}
+
+		if !strings.Contains(reply, de.Reply) {
+			t.Fatalf("error line %q is not part of the reply %q", de.Reply, reply)
+		}
+
+		// the echo is never mistaken for an error, even when the command itself looks like one
+		if strings.TrimSpace(de.Reply) == strings.TrimSpace(cmd) {
+			t.Fatalf("the echo of %q was reported as an error", cmd)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package makcu
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 🐱 One session recorded with -trace (or Trace.ExportJSONL)
+type recording struct {
+	tx, rx [][]byte // the chunks as they were written and read
+	stream []byte   // everything read, in one piece
+}
+
+// Loads the sessions in testdata/traces, which seed the fuzz targets with captured traffic. The fake_*.jsonl ones
+// were recorded against makcufake, recordings of a real MAKCU go in the same directory.
+func recordedTraffic(f *testing.F) []recording {
+	f.Helper()
+
+	paths, err := filepath.Glob("testdata/traces/*.jsonl")
+	if err != nil || len(paths) == 0 {
+		f.Fatalf("no recorded traces: %v", err)
+	}
+
+	var recs []recording
+	for _, path := range paths {
+		tr, err := ImportTraceJSONL(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		var rec recording
+		for _, e := range tr.Entries() {
+			if e.Dir == TX {
+				rec.tx = append(rec.tx, e.Data)
+				continue
+			}
+			rec.rx = append(rec.rx, e.Data)
+			rec.stream = append(rec.stream, e.Data...)
+		}
+		recs = append(recs, rec)
+	}
+
+	return recs
+}
+
+// 🐱 Every command in the recordings paired with its reply (the stream packets taken out), as parseReply sees them
+func recordedReplies(f *testing.F) (cmds, replies []string) {
+	for _, rec := range recordedTraffic(f) {
+		text, _ := runDemux([][]byte{rec.stream})
+		for _, reply := range strings.SplitAfter(text, replyPrompt) {
+			echo, _, ok := strings.Cut(reply, "\r\n")
+			if !ok {
+				continue
+			}
+
+			cmds = append(cmds, echo)
+			replies = append(replies, strings.TrimSuffix(reply, replyPrompt))
+		}
+	}
+
+	return cmds, replies
+}
+
+// 🐱 Every command line the recordings sent
+func recordedCommands(f *testing.F) []string {
+	var lines []string
+	for _, rec := range recordedTraffic(f) {
+		for _, chunk := range rec.tx {
+			for _, line := range bytes.Split(chunk, []byte{'\r'}) {
+				if len(line) > 0 {
+					lines = append(lines, string(line)+"\r")
+				}
+			}
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,239 @@
+package makcu
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 🐱 Which way a traced chunk went
+type Direction uint8
+
+const (
+	TX Direction = iota // host -> MAKCU
+	RX                  // MAKCU -> host
+)
+
+func (d Direction) String() string {
+	if d == TX {
+		return "TX"
+	}
+
+	return "RX"
+}
+
+// 🐱 One chunk written to or read from the port
+type TraceEntry struct {
+	Seq    uint64
+	Offset time.Duration // time since the trace started, taken from the monotonic clock
+	Dir    Direction
+	Data   []byte
+}
+
+// 🐱 Records every chunk that crosses the serial link. Attach one with MakcuConn.SetTrace.
+type Trace struct {
+	mu      sync.Mutex
+	start   time.Time
+	seq     uint64
+	limit   int
+	entries []TraceEntry // once limit is reached, a ring with the oldest entry at head
+	head    int
+}
+
+// 🐱 Creates a trace that keeps the most recent limit entries (0 keeps everything)
+func NewTrace(limit int) *Trace {
+	return &Trace{start: time.Now(), limit: limit}
+}
+
+// Starts recording every TX/RX chunk on this handle into t. nil stops tracing.
+// Tracing costs nothing when it is off.
+func (m *MakcuHandle) SetTrace(t *Trace) error {
+	if m == nil {
+		return fmt.Errorf("SetTrace: %w", ErrNotConnected)
+	}
+
+	m.trace.Store(t)
+	return nil
+}
+
+func (t *Trace) record(dir Direction, data []byte) {
+	at := time.Since(t.start)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.seq++
+	e := TraceEntry{
+		Seq:    t.seq,
+		Offset: at,
+		Dir:    dir,
+		Data:   append([]byte(nil), data...),
+	}
+
+	if t.limit > 0 && len(t.entries) >= t.limit {
+		// full: overwrite the oldest instead of shifting everything down, this runs for every chunk on the link
+		t.entries[t.head] = e
+		t.head = (t.head + 1) % len(t.entries)
+		return
+	}
+
+	t.entries = append(t.entries, e)
+}
+
+// 🐱 Returns a copy of the recorded entries, oldest first
+func (t *Trace) Entries() []TraceEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]TraceEntry, 0, len(t.entries))
+	out = append(out, t.entries[t.head:]...)
+	return append(out, t.entries[:t.head]...)
+}
+
+// 🐱 Drops everything recorded so far and restarts the clock
+func (t *Trace) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries = t.entries[:0]
+	t.head = 0
+	t.seq = 0
+	t.start = time.Now()
+}
+
+// 🐱🐱🐱 Cat trace! 🐱🐱🐱
+
+// 🐱 One line per chunk: "   1 +0.001234s TX "km.move(1, 2)\r"". Binary frames are decoded.
+func (t *Trace) Text() string {
+	var sb strings.Builder
+	for _, e := range t.Entries() {
+		if cmd, payload, n, err := parseFrame(e.Data); err == nil && n == len(e.Data) {
+			fmt.Fprintf(&sb, "%6d %+.6fs %s frame cmd=0x%02X payload=[% X]\n", e.Seq, e.Offset.Seconds(), e.Dir, cmd, payload)
+			continue
+		}
+
+		fmt.Fprintf(&sb, "%6d %+.6fs %s %q\n", e.Seq, e.Offset.Seconds(), e.Dir, e.Data)
+	}
+
+	return sb.String()
+}
+
+// 🐱 Same as Text but with a hexdump -C style dump of every chunk, for binary frames and stream packets
+func (t *Trace) HexDump() string {
+	var sb strings.Builder
+	for _, e := range t.Entries() {
+		fmt.Fprintf(&sb, "%6d %+.6fs %s %d bytes\n", e.Seq, e.Offset.Seconds(), e.Dir, len(e.Data))
+		sb.WriteString(hex.Dump(e.Data))
+	}
+
+	return sb.String()
+}
+
+// 🐱 JSON shape of one exported entry
+type traceRecord struct {
+	Seq  uint64 `json:"seq"`
+	TUS  int64  `json:"t_us"`
+	Dir  string `json:"dir"`
+	Hex  string `json:"hex"`
+	Text string `json:"text"`
+}
+
+// 🐱 Writes the trace as JSON lines, one object per chunk with the raw bytes in hex
+func (t *Trace) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, e := range t.Entries() {
+		err := enc.Encode(traceRecord{
+			Seq:  e.Seq,
+			TUS:  e.Offset.Microseconds(),
+			Dir:  e.Dir.String(),
+			Hex:  hex.EncodeToString(e.Data),
+			Text: string(e.Data),
+		})
+		if err != nil {
+			return fmt.Errorf("WriteJSONL: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// 🐱 Writes the trace to a JSON lines file, replacing it if it exists
+func (t *Trace) ExportJSONL(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ExportJSONL: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	if err := t.WriteJSONL(w); err != nil {
+		f.Close()
+		return fmt.Errorf("ExportJSONL: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("ExportJSONL: %w", err)
+	}
+
+	return f.Close()
+}
+
+// 🐱 Reads a trace written by WriteJSONL back in, e.g. to print it with Text or HexDump
+func ReadTraceJSONL(r io.Reader) (*Trace, error) {
+	t := &Trace{}
+	dec := json.NewDecoder(r)
+	for {
+		var rec traceRecord
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ReadTraceJSONL: entry %d: %w", len(t.entries)+1, err)
+		}
+
+		data, err := hex.DecodeString(rec.Hex)
+		if err != nil {
+			return nil, fmt.Errorf("ReadTraceJSONL: entry %d: %w", len(t.entries)+1, err)
+		}
+
+		dir := TX
+		if rec.Dir == "RX" {
+			dir = RX
+		}
+
+		t.entries = append(t.entries, TraceEntry{
+			Seq:    rec.Seq,
+			Offset: time.Duration(rec.TUS) * time.Microsecond,
+			Dir:    dir,
+			Data:   data,
+		})
+		t.seq = rec.Seq
+	}
+
+	return t, nil
+}
+
+// 🐱 Reads a JSON lines file written by ExportJSONL
+func ImportTraceJSONL(path string) (*Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ImportTraceJSONL: %w", err)
+	}
+	defer f.Close()
+
+	t, err := ReadTraceJSONL(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("ImportTraceJSONL: %w", err)
+	}
+
+	return t, nil
+}
+
+// 🐱🐱🐱 Cat trace export! 🐱🐱🐱
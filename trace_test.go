@@ -0,0 +1,81 @@
+package makcu
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTraceKeepsNewest(t *testing.T) {
+	tr := NewTrace(3)
+	for i := 1; i <= 10; i++ {
+		tr.record(TX, []byte(strconv.Itoa(i)))
+
+		entries := tr.Entries()
+		if want := min(i, 3); len(entries) != want {
+			t.Fatalf("after %d records: %d entries, want %d", i, len(entries), want)
+		}
+
+		for j, e := range entries {
+			if want := uint64(i - len(entries) + 1 + j); e.Seq != want {
+				t.Fatalf("after %d records: entry %d has seq %d, want %d", i, j, e.Seq, want)
+			}
+		}
+	}
+
+	tr.Reset()
+	tr.record(RX, []byte("x"))
+	if e := tr.Entries(); len(e) != 1 || e[0].Seq != 1 || e[0].Dir != RX {
+		t.Errorf("after Reset: %+v", e)
+	}
+}
+
+func TestTraceJSONLRoundTrip(t *testing.T) {
+	m, _ := openFake(t, "")
+	tr := NewTrace(0)
+	m.SetTrace(tr)
+
+	if err := m.MoveMouse(3, -4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Write(appendFrame(nil, frameSetBaud, []byte{1, 2, 3, 4})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Buttons(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tr.WriteJSONL(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := ReadTraceJSONL(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := tr.Entries()
+	for i := range want {
+		// JSONL keeps microseconds
+		want[i].Offset = want[i].Offset.Truncate(time.Microsecond)
+	}
+	if got := back.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func BenchmarkTraceRecordFull(b *testing.B) {
+	tr := NewTrace(10000)
+	chunk := []byte("km.move(1, 1)\r")
+	for i := 0; i < 10000; i++ {
+		tr.record(TX, chunk)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.record(TX, chunk)
+	}
+}
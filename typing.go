@@ -0,0 +1,140 @@
+package makcu
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// 🐱 Pause between characters in TypeString unless SetTypingDelay says otherwise. Hosts can drop key presses
+// that arrive back to back.
+const DefaultTypingDelay = 10 * time.Millisecond
+
+// 🐱 Sets the pause TypeString leaves after each character (0 goes back to DefaultTypingDelay)
+func (m *MakcuHandle) SetTypingDelay(perChar time.Duration) error {
+	if m == nil {
+		return fmt.Errorf("SetTypingDelay: %w", ErrNotConnected)
+	}
+
+	if perChar < 0 {
+		return fmt.Errorf("SetTypingDelay: delay must not be negative, got %v", perChar)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.typingDelay = perChar
+	return nil
+}
+
+// Types text through the MAKCU's keyboard output, as the host will read it with the given layout (LayoutUS,
+// LayoutUK, LayoutDE, LayoutFR). Shift, AltGr and dead keys are used as the layout needs them. Each character
+// is sent in one write, followed by the typing delay. Characters the layout has no key for go through the
+// UnicodeFallback set with SetUnicodeFallback, if any. If some character still can't be typed nothing is typed
+// and the *UnrepresentableError lists them all. Stops early with ctx.Err() once ctx is done.
+//
+//	err := MakcuConn.TypeString(ctx, "Hello, Welt!", makcu.LayoutDE)
+func (m *MakcuHandle) TypeString(ctx context.Context, text string, layout *Layout) error {
+	if m == nil {
+		return fmt.Errorf("TypeString: %w", ErrNotConnected)
+	}
+
+	if layout == nil {
+		return fmt.Errorf("TypeString: no layout given")
+	}
+
+	if err := m.require("TypeString", CapKeyboard); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	delay := m.typingDelay
+	fallback := m.unicodeFallback
+	m.mu.Unlock()
+	if delay == 0 {
+		delay = DefaultTypingDelay
+	}
+
+	// work out every character first so nothing is typed when one of them can't be
+	type typed struct {
+		r   rune
+		seq []KeyAction
+	}
+	var (
+		chars   []typed
+		missing []rune
+	)
+	for _, r := range text {
+		if r == '\r' {
+			// "\r\n" is one Enter, typed for the '\n'
+			continue
+		}
+
+		if strokes, ok := layout.Strokes(r); ok {
+			chars = append(chars, typed{r, strokeActions(nil, strokes)})
+			continue
+		}
+
+		if fallback != nil {
+			if seq, err := fallback.Sequence(r, layout); err == nil {
+				chars = append(chars, typed{r, seq})
+				continue
+			}
+		}
+
+		if !slices.Contains(missing, r) {
+			missing = append(missing, r)
+		}
+	}
+
+	if len(missing) > 0 {
+		e := &UnrepresentableError{Layout: layout.Name, Chars: missing}
+		if fallback != nil {
+			e.Fallback = fallback.Name()
+		}
+		return e
+	}
+
+	timer := time.NewTimer(0)
+	<-timer.C
+	defer timer.Stop()
+
+	for _, c := range chars {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := m.sendKeys(c.seq); err != nil {
+			return fmt.Errorf("TypeString: typing %q: %w", c.r, err)
+		}
+
+		timer.Reset(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// 🐱 Sends a run of key downs and ups in a single write
+func (m *MakcuHandle) sendKeys(seq []KeyAction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cmdBuf = m.cmdBuf[:0]
+	for _, a := range seq {
+		fn := "up"
+		if a.Down {
+			fn = "down"
+		}
+		m.cmdBuf = appendCall(m.cmdBuf, fn, int(a.Key))
+	}
+
+	return m.execLocked(m.cmdBuf)
+}
+
+// 🐱🐱🐱 Cat typing! 🐱🐱🐱
@@ -0,0 +1,78 @@
+package makcu
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nullpkt/Makcu-Go/makcufake"
+)
+
+// 🐱 The fake's km.down/km.up calls in FormatKeyActions form
+func fakeKeys(dev *makcufake.Device) string {
+	var parts []string
+	for _, c := range dev.Calls() {
+		a := KeyAction{Key(c.Args[0]), c.Fn == "down"}
+		parts = append(parts, a.String())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func TestTypeString(t *testing.T) {
+	m, dev := openFake(t, "v3.5.0")
+	if err := m.SetTypingDelay(time.Nanosecond); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.TypeString(context.Background(), "zy@â", LayoutDE); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "+y -y +z -z +rightalt +q -q -rightalt +grave -grave +a -a"
+	if got := fakeKeys(dev); got != want {
+		t.Errorf("typed %q, want %q", got, want)
+	}
+}
+
+// 🐱 Every character that can't be typed is listed once, in the order it first shows up, and nothing is sent
+func TestTypeStringUnrepresentable(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		fallback UnicodeFallback
+		text     string
+		want     []rune
+	}{
+		{"no fallback", nil, "日本x日é本", []rune{'日', '本', 'é'}},
+		{"AltHex", FallbackWindowsAltHex, "a😺b😺🐱", []rune{'😺', '🐱'}},
+		{"AltNumpad", FallbackWindowsAltNumpad, "é日€日", []rune{'日'}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, dev := openFake(t, "v3.5.0")
+			if err := m.SetUnicodeFallback(tc.fallback); err != nil {
+				t.Fatal(err)
+			}
+
+			err := m.TypeString(context.Background(), tc.text, LayoutUS)
+			var ue *UnrepresentableError
+			if !errors.As(err, &ue) {
+				t.Fatalf("got %v, want an UnrepresentableError", err)
+			}
+
+			if !reflect.DeepEqual(ue.Chars, tc.want) || ue.Layout != "US" {
+				t.Errorf("Chars = %q on %s, want %q on US", ue.Chars, ue.Layout, tc.want)
+			}
+
+			if tc.fallback != nil && ue.Fallback != tc.fallback.Name() {
+				t.Errorf("Fallback = %q, want %q", ue.Fallback, tc.fallback.Name())
+			}
+
+			if got := dev.Calls(); len(got) != 0 {
+				t.Errorf("sent %v anyway", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package makcu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 🐱 A single key going down or up
+type KeyAction struct {
+	Key  Key
+	Down bool
+}
+
+// 🐱 "+leftctrl" for down, "-leftctrl" for up
+func (a KeyAction) String() string {
+	if a.Down {
+		return "+" + a.Key.String()
+	}
+
+	return "-" + a.Key.String()
+}
+
+// 🐱 Expands key presses into the downs and ups that make them
+func strokeActions(dst []KeyAction, strokes []Stroke) []KeyAction {
+	for _, s := range strokes {
+		mods := s.Mod.Keys()
+		for _, mk := range mods {
+			dst = append(dst, KeyAction{mk, true})
+		}
+		dst = append(dst, KeyAction{s.Key, true}, KeyAction{s.Key, false})
+		for i := len(mods) - 1; i >= 0; i-- {
+			dst = append(dst, KeyAction{mods[i], false})
+		}
+	}
+
+	return dst
+}
+
+// 🐱 Turns a string into key actions on layout, failing if it has a character layout can't type
+func typeActions(dst []KeyAction, s string, layout *Layout) ([]KeyAction, error) {
+	for _, r := range s {
+		strokes, ok := layout.Strokes(r)
+		if !ok {
+			return nil, fmt.Errorf("the %s layout can't type %q", layout.Name, r)
+		}
+		dst = strokeActions(dst, strokes)
+	}
+
+	return dst, nil
+}
+
+// 🐱🐱🐱 Cat key actions! 🐱🐱🐱
+
+// A way to enter a character the keyboard layout has no key for, through an input method of the host.
+// Pick the one matching the PC the MAKCU is plugged into and hand it to SetUnicodeFallback.
+type UnicodeFallback interface {
+	// Name says which host it is meant for, for error messages
+	Name() string
+	// Sequence returns the key actions that enter r on a host using layout
+	Sequence(r rune, layout *Layout) ([]KeyAction, error)
+}
+
+// 🐱 The fallbacks that come with the package
+var (
+	// Ctrl+Shift+U, the code point in hex, then Space. Works in GTK apps and anywhere IBus is running, so most
+	// Linux desktops. Handles every code point, emoji included.
+	FallbackLinuxHex UnicodeFallback = linuxHex{}
+
+	// Holds Alt and types 0 and the Windows-1252 code on the numpad. Works in nearly every Windows app, but only
+	// for the characters in Windows-1252 (Latin-1 plus €, „, ™, ...). Num Lock has to be on.
+	FallbackWindowsAltNumpad UnicodeFallback = windowsAltNumpad{}
+
+	// Holds Alt and types numpad + and the code point in hex. Handles the whole Basic Multilingual Plane (no
+	// emoji), but Windows only accepts it with the registry value HKCU\Control Panel\Input Method\EnableHexNumpad
+	// set to "1" (takes effect after logging in again). Num Lock has to be on.
+	FallbackWindowsAltHex UnicodeFallback = windowsAltHex{}
+)
+
+// 🐱 Sets the fallback TypeString uses for characters the layout can't type. nil turns it off again, so those
+// characters make TypeString fail.
+func (m *MakcuHandle) SetUnicodeFallback(f UnicodeFallback) error {
+	if m == nil {
+		return fmt.Errorf("SetUnicodeFallback: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.unicodeFallback = f
+	return nil
+}
+
+// 🐱🐱🐱 Cat unicode fallback! 🐱🐱🐱
+
+type linuxHex struct{}
+
+func (linuxHex) Name() string { return "Linux Ctrl+Shift+U" }
+
+func (linuxHex) Sequence(r rune, layout *Layout) ([]KeyAction, error) {
+	// the U, the digits and the space are all key positions, so they go through the layout like any other text
+	seq := []KeyAction{{KeyLeftCtrl, true}, {KeyLeftShift, true}}
+	seq, err := typeActions(seq, "u", layout)
+	if err != nil {
+		return nil, err
+	}
+	seq = append(seq, KeyAction{KeyLeftShift, false}, KeyAction{KeyLeftCtrl, false})
+
+	return typeActions(seq, strconv.FormatInt(int64(r), 16)+" ", layout)
+}
+
+type windowsAltNumpad struct{}
+
+func (windowsAltNumpad) Name() string { return "Windows Alt+numpad" }
+
+// 🐱 Windows-1252 bytes 0x80..0x9F, which differ from Latin-1. 0 means unassigned.
+var cp1252High = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
+
+func (windowsAltNumpad) Sequence(r rune, layout *Layout) ([]KeyAction, error) {
+	code := -1
+	switch {
+	case r >= 0x20 && r < 0x80 || r >= 0xA0 && r <= 0xFF:
+		code = int(r)
+	default:
+		for i, c := range cp1252High {
+			if c == r && c != 0 {
+				code = 0x80 + i
+			}
+		}
+	}
+
+	if code < 0 {
+		return nil, fmt.Errorf("%q is not in Windows-1252", r)
+	}
+
+	// the leading 0 makes Windows read the code as Windows-1252 instead of the old OEM code page
+	return altNumpad(nil, "0"+strconv.Itoa(code)), nil
+}
+
+type windowsAltHex struct{}
+
+func (windowsAltHex) Name() string { return "Windows Alt+numpad hex" }
+
+func (windowsAltHex) Sequence(r rune, layout *Layout) ([]KeyAction, error) {
+	if r > 0xFFFF {
+		return nil, fmt.Errorf("%q is outside the Basic Multilingual Plane", r)
+	}
+
+	hex := strconv.FormatInt(int64(r), 16)
+	seq := []KeyAction{{KeyLeftAlt, true}, {KeyPadPlus, true}, {KeyPadPlus, false}}
+	for _, d := range hex {
+		if d >= '0' && d <= '9' {
+			seq = append(seq, numpadDigit(d)...)
+			continue
+		}
+
+		// a-f come from the letter keys, which the layout may have moved (they're where you'd expect on all
+		// four built in layouts, but not necessarily on others)
+		strokes, ok := layout.Strokes(d)
+		if !ok || len(strokes) != 1 || strokes[0].Mod != 0 {
+			return nil, fmt.Errorf("the %s layout has no plain key for %q", layout.Name, d)
+		}
+		seq = append(seq, KeyAction{strokes[0].Key, true}, KeyAction{strokes[0].Key, false})
+	}
+
+	return append(seq, KeyAction{KeyLeftAlt, false}), nil
+}
+
+// 🐱 Alt held while the digits are typed on the numpad
+func altNumpad(dst []KeyAction, digits string) []KeyAction {
+	dst = append(dst, KeyAction{KeyLeftAlt, true})
+	for _, d := range digits {
+		dst = append(dst, numpadDigit(d)...)
+	}
+
+	return append(dst, KeyAction{KeyLeftAlt, false})
+}
+
+func numpadDigit(d rune) []KeyAction {
+	k := KeyPad0
+	if d != '0' {
+		k = KeyPad1 + Key(d-'1')
+	}
+
+	return []KeyAction{{k, true}, {k, false}}
+}
+
+// 🐱 Formats a key sequence as "+leftalt +kp0 -kp0 ... -leftalt", handy for checking what a fallback sends
+func FormatKeyActions(seq []KeyAction) string {
+	parts := make([]string, len(seq))
+	for i, a := range seq {
+		parts[i] = a.String()
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// 🐱🐱🐱 Cat input methods! 🐱🐱🐱
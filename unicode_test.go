@@ -0,0 +1,79 @@
+package makcu
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnicodeFallbacks(t *testing.T) {
+	for _, tc := range []struct {
+		fallback UnicodeFallback
+		layout   *Layout
+		r        rune
+		want     string // "" when the fallback must refuse r
+	}{
+		{FallbackLinuxHex, LayoutUS, 'é', "+leftctrl +leftshift +u -u -leftshift -leftctrl +e -e +9 -9 +space -space"},
+		{FallbackLinuxHex, LayoutUS, '😺', "+leftctrl +leftshift +u -u -leftshift -leftctrl +1 -1 +f -f +6 -6 +3 -3 +a -a +space -space"},
+		// the digits are on Shift and a is where q is on US
+		{FallbackLinuxHex, LayoutFR, '😺', "+leftctrl +leftshift +u -u -leftshift -leftctrl " +
+			"+leftshift +1 -1 -leftshift +f -f +leftshift +6 -6 -leftshift +leftshift +3 -3 -leftshift +q -q +space -space"},
+		{FallbackLinuxHex, LayoutDE, 'ñ', "+leftctrl +leftshift +u -u -leftshift -leftctrl +f -f +1 -1 +space -space"},
+
+		{FallbackWindowsAltNumpad, LayoutUS, 'é', "+leftalt +kp0 -kp0 +kp2 -kp2 +kp3 -kp3 +kp3 -kp3 -leftalt"},
+		{FallbackWindowsAltNumpad, LayoutUS, '€', "+leftalt +kp0 -kp0 +kp1 -kp1 +kp2 -kp2 +kp8 -kp8 -leftalt"},
+		{FallbackWindowsAltNumpad, LayoutUS, 'Ÿ', "+leftalt +kp0 -kp0 +kp1 -kp1 +kp5 -kp5 +kp9 -kp9 -leftalt"},
+		{FallbackWindowsAltNumpad, LayoutUS, '日', ""},
+		{FallbackWindowsAltNumpad, LayoutUS, '\u0081', ""},
+		{FallbackWindowsAltNumpad, LayoutUS, '😺', ""},
+
+		{FallbackWindowsAltHex, LayoutUS, 'é', "+leftalt +kpplus -kpplus +e -e +kp9 -kp9 -leftalt"},
+		{FallbackWindowsAltHex, LayoutUS, '日', "+leftalt +kpplus -kpplus +kp6 -kp6 +kp5 -kp5 +e -e +kp5 -kp5 -leftalt"},
+		{FallbackWindowsAltHex, LayoutFR, 'ª', "+leftalt +kpplus -kpplus +q -q +q -q -leftalt"},
+		{FallbackWindowsAltHex, LayoutUS, '😺', ""},
+		{FallbackWindowsAltHex, LayoutUS, '\U00010000', ""},
+	} {
+		name := tc.fallback.Name() + " on " + tc.layout.Name
+		seq, err := tc.fallback.Sequence(tc.r, tc.layout)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("%s: %q gave %q, want an error", name, tc.r, FormatKeyActions(seq))
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %q: %v", name, tc.r, err)
+			continue
+		}
+
+		if got := FormatKeyActions(seq); got != tc.want {
+			t.Errorf("%s: %q =\n%s\nwant\n%s", name, tc.r, got, tc.want)
+		}
+
+		// whatever a fallback holds down it lets go of again
+		held := map[Key]bool{}
+		for _, a := range seq {
+			held[a.Key] = a.Down
+		}
+		for k, down := range held {
+			if down {
+				t.Errorf("%s: %q leaves %s down", name, tc.r, k)
+			}
+		}
+	}
+}
+
+func TestFormatKeyActions(t *testing.T) {
+	seq := []KeyAction{{KeyLeftCtrl, true}, {KeyC, true}, {KeyC, false}, {KeyLeftCtrl, false}}
+	if got, want := FormatKeyActions(seq), "+leftctrl +c -c -leftctrl"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := FormatKeyActions(nil); got != "" {
+		t.Errorf("nil: got %q", got)
+	}
+
+	if s := (KeyAction{Key(0xE8), true}).String(); !strings.HasPrefix(s, "+Key(") {
+		t.Errorf("unnamed key: got %q", s)
+	}
+}
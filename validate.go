@@ -0,0 +1,106 @@
+package makcu
+
+import "fmt"
+
+// 🐱 Largest distance one km.move can carry on either axis (the firmware keeps it in a signed 16 bit value)
+const (
+	MoveMin = -32767
+	MoveMax = 32767
+)
+
+// 🐱 Returned when an argument is outside what the firmware accepts. errors.Is(err, ErrOutOfRange) matches it.
+type RangeError struct {
+	Op    string
+	Arg   string
+	Value int
+	Min   int
+	Max   int
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%s: %s = %d is out of range [%d, %d]", e.Op, e.Arg, e.Value, e.Min, e.Max)
+}
+
+func (e *RangeError) Unwrap() error {
+	return ErrOutOfRange
+}
+
+// 🐱 Returns a *RangeError if v is outside [min, max]
+func checkRange(op, arg string, v, min, max int) error {
+	if v < min || v > max {
+		return &RangeError{Op: op, Arg: arg, Value: v, Min: min, Max: max}
+	}
+
+	return nil
+}
+
+// 🐱 Checks a relative move, naming the first axis that doesn't fit
+func checkMove(op string, x, y int) error {
+	if err := checkRange(op, "x", x, MoveMin, MoveMax); err != nil {
+		return err
+	}
+
+	return checkRange(op, "y", y, MoveMin, MoveMax)
+}
+
+// When on, moves bigger than MoveMax are cut into several in-range km.move commands that add up to the request
+// (sent together in one write) instead of failing with a *RangeError. Off by default. A split curve needs at least
+// one segment per piece, so one cut into more pieces than it has segments gets one segment per piece instead.
+func (m *MakcuHandle) SetSplitMoves(split bool) error {
+	if m == nil {
+		return fmt.Errorf("SetSplitMoves: %w", ErrNotConnected)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.splitMoves = split
+	return nil
+}
+
+// 🐱 Reports whether SetSplitMoves is on
+func (m *MakcuHandle) splitting() bool {
+	if m == nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.splitMoves
+}
+
+// 🐱🐱🐱 Cat validation! 🐱🐱🐱
+
+// 🐱 How many pieces are needed so that every value, divided evenly, fits in [-limit, limit]
+func pieces(limit int, vals ...int) int {
+	n := 1
+	for _, v := range vals {
+		if v < 0 {
+			v = -v
+		}
+
+		if k := (v + limit - 1) / limit; k > n {
+			n = k
+		}
+	}
+
+	return n
+}
+
+// 🐱 The i-th of n near equal parts of total. The parts always add up to exactly total.
+func share(total, n, i int) int {
+	return total*(i+1)/n - total*i/n
+}
+
+// 🐱 Appends a relative move as km.move commands, as many as needed to keep each one in range
+func appendMoves(dst []byte, x, y int) []byte {
+	n := pieces(MoveMax, x, y)
+	for i := 0; i < n; i++ {
+		dst = appendCall(dst, "move", share(x, n, i), share(y, n, i))
+	}
+
+	return dst
+}
+
+// 🐱🐱🐱 Cat move splitting! 🐱🐱🐱
@@ -0,0 +1,123 @@
+package makcu
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nullpkt/Makcu-Go/makcufake"
+)
+
+func TestRangeError(t *testing.T) {
+	m, dev := openFake(t, "v3.0.0")
+	for _, tc := range []struct {
+		name string
+		fn   func() error
+		want RangeError
+	}{
+		{"MoveMouse x", func() error { return m.MoveMouse(40000, 0) }, RangeError{"MoveMouse", "x", 40000, MoveMin, MoveMax}},
+		{"MoveMouse y", func() error { return m.MoveMouse(0, -32768) }, RangeError{"MoveMouse", "y", -32768, MoveMin, MoveMax}},
+		{"curve x", func() error { return m.MoveMouseWithCurve(-40000, 0, 10) }, RangeError{"MoveMouseWithCurve", "x", -40000, MoveMin, MoveMax}},
+		{"curve cx", func() error { return m.MoveMouseWithCurve(10, 0, 10, 50000, 0) }, RangeError{"MoveMouseWithCurve", "cx", 50000, MoveMin, MoveMax}},
+		{"curve segments", func() error { return m.MoveMouseWithCurve(10, 0, 0) }, RangeError{"MoveMouseWithCurve", "segments", 0, 1, MoveMax}},
+		{"Batch.Move", func() error { return m.Batch().LeftDown().Move(1, 99999).Flush() }, RangeError{"Batch.Move", "y", 99999, MoveMin, MoveMax}},
+		{"Batch.Scroll", func() error { return m.Batch().Scroll(-128).LeftDown().Flush() }, RangeError{"Batch.Scroll", "amount", -128, -127, 127}},
+		{"ScrollMouse", func() error { return m.ScrollMouse(200) }, RangeError{"KmWheel", "amount", 200, -127, 127}},
+	} {
+		err := tc.fn()
+		var re *RangeError
+		if !errors.Is(err, ErrOutOfRange) || !errors.As(err, &re) {
+			t.Errorf("%s: got %v, want a *RangeError", tc.name, err)
+			continue
+		}
+
+		if *re != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, *re, tc.want)
+		}
+	}
+
+	if got := dev.Calls(); len(got) != 0 {
+		t.Errorf("sent %v anyway", got)
+	}
+}
+
+// 🐱 Sums up the km.move calls the fake got, checking each one was in range
+func moveTotals(t *testing.T, calls []makcufake.Call) (x, y, segments int) {
+	t.Helper()
+
+	for _, c := range calls {
+		if c.Fn != "move" {
+			t.Errorf("unexpected call %v", c)
+			continue
+		}
+
+		x, y = x+c.Args[0], y+c.Args[1]
+		if len(c.Args) > 2 {
+			segments += c.Args[2]
+		}
+	}
+
+	return x, y, segments
+}
+
+func TestSplitMoves(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		fn       func(m *MakcuHandle) error
+		x, y     int
+		segments int
+		calls    int
+	}{
+		{"MoveMouse", func(m *MakcuHandle) error { return m.MoveMouse(70000, -3) }, 70000, -3, 0, 3},
+		{"MoveMouse both axes", func(m *MakcuHandle) error { return m.MoveMouse(-40000, 65535) }, -40000, 65535, 0, 3},
+		{"MoveMouse in range", func(m *MakcuHandle) error { return m.MoveMouse(MoveMax, MoveMin) }, MoveMax, MoveMin, 0, 1},
+		{"Batch.Move", func(m *MakcuHandle) error { return m.Batch().Move(100000, 1).Flush() }, 100000, 1, 0, 4},
+		{"curve", func(m *MakcuHandle) error { return m.MoveMouseWithCurve(70000, -3, 30) }, 70000, -3, 30, 3},
+		{"curve bezier", func(m *MakcuHandle) error { return m.MoveMouseWithCurve(50000, 20000, 40, 10000, 60000) }, 50000, 20000, 40, 4},
+		{"curve far control point", func(m *MakcuHandle) error { return m.MoveMouseWithCurve(1000, -1000, 9, 0, 90000) }, 1000, -1000, 9, 6},
+		// more pieces than segments, so each piece gets one
+		{"curve one segment", func(m *MakcuHandle) error { return m.MoveMouseWithCurve(70000, -3, 1) }, 70000, -3, 3, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, dev := openFake(t, "v3.0.0")
+			if err := m.SetSplitMoves(true); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tc.fn(m); err != nil {
+				t.Fatal(err)
+			}
+
+			calls := dev.Calls()
+			if len(calls) != tc.calls {
+				t.Errorf("%d calls, want %d: %v", len(calls), tc.calls, callNames(dev))
+			}
+
+			x, y, segments := moveTotals(t, calls)
+			if x != tc.x || y != tc.y || segments != tc.segments {
+				t.Errorf("moved (%d, %d) in %d segments, want (%d, %d) in %d", x, y, segments, tc.x, tc.y, tc.segments)
+			}
+
+			if rejected := dev.Rejected(); len(rejected) != 0 {
+				t.Errorf("the fake rejected %v", rejected)
+			}
+		})
+	}
+}
+
+// 🐱 A batch that failed sends nothing and can be used again
+func TestBatchReuseAfterError(t *testing.T) {
+	m, dev := openFake(t, "")
+	b := m.Batch().Move(1, 1).Move(40000, 0).Move(2, 2)
+	if err := b.Flush(); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("got %v, want ErrOutOfRange", err)
+	}
+
+	if err := b.Move(3, 3).Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := callNames(dev), []string{"Move(3, 3)"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}